@@ -0,0 +1,45 @@
+package builder
+
+// Settings shared by every attempt of a single labyrinth generation
+type generation struct {
+	hook    Hook
+	logger  Logger
+	checker ChoiceChecker // built once from configuration, decides which cells routes can grow into
+	started uint          // highest number of the attempts started so far
+}
+
+// Start the attempt with the chosen number, attempts are started from a single goroutine
+func (g *generation) attempt(number uint) *Attempt {
+	if number > g.started {
+		g.started = number
+	}
+
+	return &Attempt{Number: number, generation: g}
+}
+
+// Single attempt of the labyrinth generation, generators report their steps through it
+type Attempt struct {
+	Number     uint // starting with 1
+	generation *generation
+	routes     routesSummary // filled by the algorithms that grow routes
+}
+
+// Report the step of the attempt to the hook, if there is one
+func (a *Attempt) Report(p Progress) {
+	if a.generation != nil && a.generation.hook != nil {
+		p.Attempt = a.Number
+		a.generation.hook(p)
+	}
+}
+
+// Check that there is a logger, so that arguments costly to compute are skipped without one
+func (a *Attempt) logging() bool {
+	return a.generation != nil && a.generation.logger != nil
+}
+
+// Write the message to the logger, nothing is written without one
+func (a *Attempt) logf(format string, v ...any) {
+	if a.logging() {
+		a.generation.logger.Printf(format, v...)
+	}
+}
@@ -0,0 +1,175 @@
+package builder
+
+import (
+	"math"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Check that the walkable cell has only one way out, start, finish and checkpoints are not dead ends
+func isDeadEnd(f *core.Field, coords core.Coordinates) bool {
+	cell, err := f.At(coords)
+
+	return err == nil && cell != core.Start && cell != core.Finish && cell != core.Checkpoint &&
+		cell.IsWalkable() && countWalkableNeighbors(f, coords) == 1
+}
+
+// Find walkable cells with only one way out, start, finish and checkpoints are not counted
+func findDeadEnds(f *core.Field) []core.Coordinates {
+	dead_ends := make([]core.Coordinates, 0)
+	for y := 0; y < int(f.Length); y++ {
+		for x := 0; x < int(f.Width); x++ {
+			if coords := (core.Coordinates{X: x, Y: y}); isDeadEnd(f, coords) {
+				dead_ends = append(dead_ends, coords)
+			}
+		}
+	}
+
+	return dead_ends
+}
+
+// Count dead ends among the wall and the cells next to it, which are the only ones knocking it through can change
+func countDeadEndsAround(f *core.Field, wall core.Coordinates) int {
+	counter := 0
+	if isDeadEnd(f, wall) {
+		counter++
+	}
+	for _, shift := range NeumannShifts {
+		if isDeadEnd(f, core.Coordinates{X: wall.X + shift[0], Y: wall.Y + shift[1]}) {
+			counter++
+		}
+	}
+
+	return counter
+}
+
+// Check that the wall can be knocked through to join the dead end with another corridor
+// Masked out cells stay walls, as well as the ones around the finish if there should be only one path near it
+func canKnockThrough(f *core.Field, wall, dead_end core.Coordinates) bool {
+	if cell, err := f.UsableAt(wall); err != nil || cell != core.Wall {
+		return false
+	}
+	if f.Configuration.Builder.OnlyOnePathNearFinish && math.Abs(float64(wall.X-f.Finish.X)) <= 1 && math.Abs(float64(wall.Y-f.Finish.Y)) <= 1 {
+		return false
+	}
+	for _, shift := range NeumannShifts {
+		neighbor := core.Coordinates{X: wall.X + shift[0], Y: wall.Y + shift[1]}
+		if neighbor != dead_end && isWalkable(f, neighbor) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Walkable cells connected to each other without going through each of the checkpoints
+// Knocking a wall through only joins the sets around it, so checking a wall does not need to walk the labyrinth
+type checkpointBypasses struct {
+	f    *core.Field
+	sets []roomSets // sets of cell indices, one for every checkpoint
+}
+
+// Connect every walkable cell with its neighbors, leaving out one checkpoint at a time
+func newCheckpointBypasses(f *core.Field) checkpointBypasses {
+	b := checkpointBypasses{f: f, sets: make([]roomSets, len(f.Checkpoints))}
+	for i, checkpoint := range f.Checkpoints {
+		b.sets[i] = newRoomSets(int(f.Size()))
+		for y := 0; y < int(f.Length); y++ {
+			for x := 0; x < int(f.Width); x++ {
+				coords := core.Coordinates{X: x, Y: y}
+				if coords == checkpoint || !isWalkable(f, coords) {
+					continue
+				}
+				// every pair of neighbors is joined once, from its left or top cell
+				for _, neighbor := range []core.Coordinates{{X: x + 1, Y: y}, {X: x, Y: y + 1}} {
+					if neighbor != checkpoint && isWalkable(f, neighbor) {
+						b.sets[i].union(cellIndex(f, coords), cellIndex(f, neighbor))
+					}
+				}
+			}
+		}
+	}
+
+	return b
+}
+
+// Check that knocking the wall through would open a way from start to finish around one of the checkpoints
+func (b checkpointBypasses) opens(wall core.Coordinates) bool {
+	for i, checkpoint := range b.f.Checkpoints {
+		start, finish := b.sets[i].find(cellIndex(b.f, b.f.Start)), b.sets[i].find(cellIndex(b.f, b.f.Finish))
+		joins_start, joins_finish := false, false
+		for _, shift := range NeumannShifts {
+			neighbor := core.Coordinates{X: wall.X + shift[0], Y: wall.Y + shift[1]}
+			if neighbor != checkpoint && isWalkable(b.f, neighbor) {
+				set := b.sets[i].find(cellIndex(b.f, neighbor))
+				joins_start, joins_finish = joins_start || set == start, joins_finish || set == finish
+			}
+		}
+		if joins_start && joins_finish {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Join the knocked through wall with the walkable cells around it
+func (b checkpointBypasses) knock(wall core.Coordinates) {
+	for i, checkpoint := range b.f.Checkpoints {
+		for _, shift := range NeumannShifts {
+			neighbor := core.Coordinates{X: wall.X + shift[0], Y: wall.Y + shift[1]}
+			if neighbor != checkpoint && isWalkable(b.f, neighbor) {
+				b.sets[i].union(cellIndex(b.f, wall), cellIndex(b.f, neighbor))
+			}
+		}
+	}
+}
+
+// Remove the configured percentage of dead ends by knocking through walls into neighbouring corridors
+// Walls are only ever removed, so every cell stays reachable and the dead ends turn into loops
+// A wall is kept if knocking it through would open a way around one of the checkpoints
+func braidDeadEnds(f *core.Field, rng *rand.Rand) error {
+	percentage := f.Configuration.Braid.Percentage
+	if percentage == 0 {
+		return nil
+	}
+
+	dead_ends := findDeadEnds(f)
+	rng.Shuffle(len(dead_ends), func(i, j int) { dead_ends[i], dead_ends[j] = dead_ends[j], dead_ends[i] })
+	dead_ends_count := len(dead_ends)
+	dead_ends_left := len(dead_ends) - int(math.Round(float64(len(dead_ends))*percentage/100))
+	bypasses := newCheckpointBypasses(f)
+	for _, dead_end := range dead_ends {
+		if dead_ends_count <= dead_ends_left {
+			return nil
+		}
+		// one of the previous walls might have already joined this dead end with another corridor
+		if countWalkableNeighbors(f, dead_end) != 1 {
+			continue
+		}
+
+		walls := make([]core.Coordinates, 0, len(NeumannShifts))
+		for _, shift := range NeumannShifts {
+			wall := core.Coordinates{X: dead_end.X + shift[0], Y: dead_end.Y + shift[1]}
+			if canKnockThrough(f, wall, dead_end) {
+				walls = append(walls, wall)
+			}
+		}
+		rng.Shuffle(len(walls), func(i, j int) { walls[i], walls[j] = walls[j], walls[i] })
+		for _, wall := range walls {
+			if bypasses.opens(wall) {
+				continue
+			}
+			dead_ends_before := countDeadEndsAround(f, wall)
+			if err := f.Set(core.Empty, wall); err != nil {
+				return err
+			}
+			bypasses.knock(wall)
+			dead_ends_count += countDeadEndsAround(f, wall) - dead_ends_before
+			break
+		}
+	}
+
+	return nil
+}
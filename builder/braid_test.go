@@ -0,0 +1,72 @@
+package builder
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Braiding stops as soon as the configured share of dead ends is gone, every cell stays reachable
+func TestBraidDeadEnds(t *testing.T) {
+	for _, percentage := range []float64{25, 50, 100} {
+		for seed := int64(0); seed < 3; seed++ {
+			f := newTestField(t, 21, 21)
+			f.Configuration.Builder.Algorithm = "recursive-backtracker"
+			if _, err := GenerateLabyrinth(f, WithSeed(seed)); err != nil {
+				t.Fatal(err)
+			}
+			reachable := countReachable(f)
+			before := len(findDeadEnds(f))
+			f.Configuration.Braid.Percentage = percentage
+			if err := braidDeadEnds(f, rand.New(rand.NewSource(seed))); err != nil {
+				t.Fatal(err)
+			}
+
+			// a single wall can join two dead ends at once
+			left, after := before-int(math.Round(float64(before)*percentage/100)), len(findDeadEnds(f))
+			if percentage < 100 && (after > left || after < left-1) {
+				t.Errorf("%v%%, seed %v: %v dead ends out of %v are left, expected %v", percentage, seed, after, before, left)
+			}
+			if after >= before {
+				t.Errorf("%v%%, seed %v: none of the %v dead ends were removed", percentage, seed, before)
+			}
+			if countReachable(f) < reachable || !reachesFinish(f) {
+				t.Errorf("%v%%, seed %v: braiding cut off a part of the labyrinth\n%v", percentage, seed, f)
+			}
+		}
+	}
+}
+
+// Count walkable cells that can be reached from the start
+func countReachable(f *core.Field) int {
+	counter := 0
+	for _, distance := range distancesFrom(f, f.Start, func(c core.Coordinates) bool { return isWalkable(f, c) }) {
+		if distance != -1 {
+			counter++
+		}
+	}
+
+	return counter
+}
+
+// Braiding never opens a way around a checkpoint
+func TestBraidKeepsCheckpointsMandatory(t *testing.T) {
+	for seed := int64(0); seed < 2; seed++ {
+		f := newTestField(t, 15, 15)
+		f.Configuration.Braid.Percentage = 100
+		if err := f.SetCheckpoints(core.Coordinates{X: 7, Y: 7}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := GenerateLabyrinth(f, WithSeed(seed)); err != nil {
+			t.Fatalf("seed %v: %v", seed, err)
+		}
+		for _, checkpoint := range f.Checkpoints {
+			distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return c != checkpoint && isWalkable(f, c) })
+			if distances[cellIndex(f, f.Finish)] != -1 {
+				t.Errorf("seed %v: checkpoint %v can be bypassed\n%v", seed, checkpoint, f)
+			}
+		}
+	}
+}
@@ -1,21 +1,24 @@
 package builder
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	core "github.com/Via-R/labyrinth-go/core"
 	"math/rand"
+	"time"
 )
 
 // Arrays of all possible coordinates' shifts when going around Von Neumann's and Moore's neighborhoods
 var NeumannShifts = [4][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}}
 var MooreShifts = [8][2]int{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
 
-// Count blocking cells around the chosen coordinates
+// Count blocking cells around the chosen coordinates, masked out cells are not counted just like the ones out of bounds
 func countWallsAround(f *core.Field, coords core.Coordinates, finish_reached bool) uint {
 	counter := uint(0)
 	for _, shift := range MooreShifts {
 		neighbor := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
-		if cell, err := f.At(neighbor); err == nil && cell.IsBlocking(finish_reached) {
+		if cell, err := f.UsableAt(neighbor); err == nil && cell.IsBlocking(finish_reached) {
 			counter++
 		}
 	}
@@ -23,21 +26,12 @@ func countWallsAround(f *core.Field, coords core.Coordinates, finish_reached boo
 	return counter
 }
 
-// Check that the cell can be a part of the route with one of the available ChoiceChecker's
-func isChoiceValid(f *core.Field, coords core.Coordinates, finish_reached bool) bool {
-	checker := isChoiceValidBy2CloseBlocksGetter()
-
-	return checker(f, coords, finish_reached)
-}
-
-// Find all possible choices from given coordinates
-// NOTE: it might make sense to use the "no more than N blocking cells around" in case
-// we don't want to have crossing routes
-func findChoices(f *core.Field, coords core.Coordinates, finish_reached bool) ([]core.Coordinates, error) {
+// Find all possible choices from given coordinates, the checker decides which cells can be a part of the route
+func findChoices(f *core.Field, checker ChoiceChecker, coords core.Coordinates, finish_reached bool) ([]core.Coordinates, error) {
 	choices := make([]core.Coordinates, 0, 4)
 	for _, shift := range NeumannShifts {
 		choice := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
-		if cell, err := f.At(choice); err == nil && !cell.IsBlocking(finish_reached) && isChoiceValid(f, choice, finish_reached) {
+		if cell, err := f.At(choice); err == nil && !cell.IsBlocking(finish_reached) && checker(f, choice, finish_reached) {
 			choices = append(choices, choice)
 		}
 	}
@@ -45,26 +39,27 @@ func findChoices(f *core.Field, coords core.Coordinates, finish_reached bool) ([
 	return choices, nil
 }
 
-// Select one of the choices based on distance to finish
+// Select one of the choices to continue the route with, based on distance to finish
 // Choices are made based on probability, which is proportionate to the distance to finish
-// Probabilities are flipped if complexity is high enough
-func selectChoice(f *core.Field, choices []core.Coordinates) (core.Coordinates, error) {
+// Probabilities are flipped if complexity is high enough, which is reported as the second return value
+// Then they are shifted towards horizontal or vertical moves and towards keeping or changing the route's direction, as configured
+func selectChoice(f *core.Field, rng *rand.Rand, route core.Route, choices []core.Coordinates) (core.Coordinates, bool, error) {
 	switch len(choices) {
 	case 0:
-		return core.Coordinates{X: -1, Y: -1}, f.Error("Cannot make a choice out of zero length array")
+		return core.Coordinates{X: -1, Y: -1}, false, f.Error("Cannot make a choice out of zero length array")
 	case 1:
-		return choices[0], nil
+		return choices[0], false, nil
 	}
 
 	for _, choice := range choices {
 		if choice == f.Finish {
-			return choice, nil
+			return choice, false, nil
 		}
 	}
 
 	distances, probabilities, probability_limits := make([]float64, len(choices)), make([]float64, len(choices)), make([]float64, len(choices))
 	sum := 0.
-	reverse_distances := rand.Float64()*100 < f.Configuration.Builder.Complexity
+	reverse_distances := rng.Float64()*100 < f.Configuration.Builder.Complexity
 	for i := range choices {
 		distances[i] = 1 / choices[i].Distance(f.Finish)
 		if reverse_distances {
@@ -72,10 +67,28 @@ func selectChoice(f *core.Field, choices []core.Coordinates) (core.Coordinates,
 		}
 		sum += distances[i]
 	}
+	if bias := f.Configuration.Builder.HorizontalBias / 100; bias != 0 {
+		biased_distances, biased_sum := make([]float64, len(choices)), 0.
+		for i, choice := range choices {
+			if choice.Y == route.End.Coords.Y {
+				biased_distances[i] = distances[i] * (1 + bias)
+			} else {
+				biased_distances[i] = distances[i] * (1 - bias)
+			}
+			biased_sum += biased_distances[i]
+		}
+		// full bias cannot leave the route without any choice, so it is ignored if only the other kind of moves is left
+		if biased_sum > 0 {
+			distances, sum = biased_distances, biased_sum
+		}
+	}
 
 	for i := range distances {
 		probabilities[i] = distances[i] / sum
 	}
+	if straightness := f.Configuration.Builder.Straightness / 100; straightness != 0 && route.End.Prev != nil {
+		keepDirection(route, choices, probabilities, straightness)
+	}
 
 	sum = 0
 	for i := range probabilities {
@@ -83,8 +96,9 @@ func selectChoice(f *core.Field, choices []core.Coordinates) (core.Coordinates,
 		sum += probabilities[i]
 	}
 
-	choice_cursor := rand.Float64()
-	choice_idx := -1
+	choice_cursor := rng.Float64()
+	// shifted probabilities might add up to slightly less than 1, so the cursor can end up past the last limit
+	choice_idx := len(choices) - 1
 	for i, limit := range probability_limits {
 		if choice_cursor < limit {
 			choice_idx = i
@@ -92,28 +106,83 @@ func selectChoice(f *core.Field, choices []core.Coordinates) (core.Coordinates,
 		}
 	}
 
-	return choices[choice_idx], nil
+	return choices[choice_idx], reverse_distances, nil
+}
+
+// Shift the probabilities of the choices towards going straight ahead if straightness is positive, or towards turning if it is negative
+// Straightness is the share of the probability moved, so 1 always keeps the direction and -1 always turns when it is possible
+// Turning is not pushed right after a turn, the zigzags it would make leave no room for the routes grown off them
+func keepDirection(route core.Route, choices []core.Coordinates, probabilities []float64, straightness float64) {
+	ahead := core.Coordinates{X: 2*route.End.Coords.X - route.End.Prev.Coords.X, Y: 2*route.End.Coords.Y - route.End.Prev.Coords.Y}
+	ahead_idx := -1
+	for i, choice := range choices {
+		if choice == ahead {
+			ahead_idx = i
+		}
+	}
+	if ahead_idx == -1 {
+		return
+	}
+
+	if straightness < 0 && route.End.Prev.Prev != nil {
+		prev, before := route.End.Prev.Coords, route.End.Prev.Prev.Coords
+		if route.End.Coords != (core.Coordinates{X: 2*prev.X - before.X, Y: 2*prev.Y - before.Y}) {
+			return
+		}
+	}
+
+	if straightness > 0 {
+		for i := range probabilities {
+			probabilities[i] *= 1 - straightness
+		}
+		probabilities[ahead_idx] += straightness
+	} else if turning := 1 - probabilities[ahead_idx]; turning > 0 {
+		moved := -straightness * probabilities[ahead_idx]
+		for i := range probabilities {
+			if i != ahead_idx {
+				probabilities[i] += moved * probabilities[i] / turning
+			}
+		}
+		probabilities[ahead_idx] -= moved
+	}
 }
 
 // Continue the given route until it gets stuck or reaches the finish
-func reachFinishOrLoop(f *core.Field, route core.Route, finish_reached bool) (core.Route, error) {
+// Every carved cell is reported as a part of the route with the given number and belongs to the section of the cell the route starts from
+func reachFinishOrLoop(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt, sections *routeSections, route core.Route, route_number uint, finish_reached bool) (core.Route, error) {
 	safety_counter := 0
 	const safety_limit = 10000
+	section := sections.of(route.End.Coords)
 
 	for route.End.Coords != f.Finish && safety_counter < safety_limit {
-		choices, err := findChoices(f, route.End.Coords, finish_reached)
+		if err := ctx.Err(); err != nil {
+			return core.Route{}, err
+		}
+		choices, err := findChoices(f, attempt.generation.checker, route.End.Coords, finish_reached)
 		if err != nil {
 			return core.Route{}, err
 		}
+		choices = sections.filter(choices, route.End.Coords)
 
 		if len(choices) == 0 {
 			return route, nil
 		}
 
-		next_coords, err := selectChoice(f, choices)
+		next_coords, away_from_finish, err := selectChoice(f, rng, route, choices)
+		if err != nil {
+			return core.Route{}, err
+		}
 		if err = f.Set(core.Path, next_coords); err != nil {
 			return core.Route{}, err
 		}
+		sections.mark(next_coords, section)
+		attempt.Report(Progress{
+			Kind:           CellCarved,
+			Coords:         next_coords,
+			Route:          route_number,
+			Choices:        uint(len(choices)),
+			AwayFromFinish: away_from_finish,
+		})
 
 		route.Add(next_coords)
 		safety_counter++
@@ -126,109 +195,408 @@ func reachFinishOrLoop(f *core.Field, route core.Route, finish_reached bool) (co
 	return route, nil
 }
 
-// Go through all provided routes and update their BaseIndices field
-func processRoutesForBaseCompatibility(f *core.Field, routes *[]core.Route, finish_reached bool) {
-	for route_idx, route := range *routes {
-		it := route.GetIterator()
-		base_indices := make([]uint, 0, route.Length)
-		route_part_idx := uint(0)
-		for coords, is_end := it(); !is_end; coords, is_end = it() {
-			if options, err := findChoices(f, coords, finish_reached); err == nil && len(options) > 0 {
-				base_indices = append(base_indices, route_part_idx)
+// Select the choice closest to the target by the distances to it
+// A random choice is made instead with the probability of complexity, which is reported as the second return value
+func selectTowardsTarget(f *core.Field, rng *rand.Rand, choices []core.Coordinates, distances []int) (core.Coordinates, bool) {
+	if len(choices) > 1 && rng.Float64()*100 < f.Configuration.Builder.Complexity {
+		return choices[rng.Intn(len(choices))], true
+	}
+
+	closest := choices[0]
+	for _, choice := range choices[1:] {
+		if distances[cellIndex(f, choice)] < distances[cellIndex(f, closest)] {
+			closest = choice
+		}
+	}
+
+	return closest, false
+}
+
+// Continue the route until it reaches the target, all carved cells belong to the chosen section
+// The route always has a plan of the rest of the way that keeps the goals after the target reachable, so it never has to go back
+// Choices allowed by the checker are taken whenever the way from them can be planned, otherwise the route follows the plan
+func carveSection(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt, sections *routeSections, route core.Route, target core.Coordinates, section int, goals []core.Coordinates) (core.Route, error) {
+	// finish blocks the choices until every checkpoint is visited
+	finish_blocking := target != f.Finish
+	distances := sections.distancesTo(target, section)
+	plan := sections.plan(route.End.Coords, distances)
+	if plan == nil || !sections.fits(plan, target, section, goals) {
+		return core.Route{}, f.Error(fmt.Sprintf("Route cannot reach %v without cutting off the goals after it", target))
+	}
+
+	for len(plan) > 0 {
+		if err := ctx.Err(); err != nil {
+			return core.Route{}, err
+		}
+
+		found_choices, err := findChoices(f, attempt.generation.checker, route.End.Coords, finish_blocking)
+		if err != nil {
+			return core.Route{}, err
+		}
+		choices := make([]core.Coordinates, 0, len(found_choices))
+		for _, choice := range found_choices {
+			if distances[cellIndex(f, choice)] != -1 {
+				choices = append(choices, choice)
 			}
-			route_part_idx++
 		}
-		(*routes)[route_idx].BaseIndices = base_indices
+
+		choices_amount := uint(len(choices))
+		next_coords, away_from_finish := plan[0], false
+		next_plan := plan[1:]
+		for len(choices) > 0 {
+			choice, away := selectTowardsTarget(f, rng, choices, distances)
+			if choice == plan[0] {
+				away_from_finish = away
+				break
+			}
+			if choice_plan := append([]core.Coordinates{choice}, sections.plan(choice, distances)...); sections.fits(choice_plan, target, section, goals) {
+				next_coords, away_from_finish, next_plan = choice, away, choice_plan[1:]
+				break
+			}
+			for i := range choices {
+				if choices[i] == choice {
+					choices = append(choices[:i], choices[i+1:]...)
+					break
+				}
+			}
+		}
+
+		f.Set(core.Path, next_coords)
+		sections.mark(next_coords, section)
+		attempt.Report(Progress{
+			Kind:           CellCarved,
+			Coords:         next_coords,
+			Route:          1,
+			Choices:        choices_amount,
+			AwayFromFinish: away_from_finish,
+		})
+		route.Add(next_coords)
+		plan = next_plan
+		distances = sections.distancesTo(target, section)
 	}
+	route.Add(target)
+
+	return route, nil
 }
 
-// Remove routes that do not have possible bases for new routes
-func removeNonBaseRoutes(routes *[]core.Route) {
-	idx := 0
-	for _, route := range *routes {
-		if len(route.BaseIndices) > 0 {
-			(*routes)[idx] = route
-			idx++
+// Carve the route from start through every checkpoint in order to the finish
+// The route between two checkpoints is a section of its own, other routes never touch cells of the other sections afterwards,
+// so this route stays the only way to the finish
+func reachCheckpoints(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt, sections *routeSections, route core.Route) (core.Route, error) {
+	attempt.Report(Progress{Kind: RouteStarted, Coords: route.End.Coords, Route: 1})
+	targets := append(append([]core.Coordinates{}, f.Checkpoints...), f.Finish)
+	for section, target := range targets {
+		var err error
+		if route, err = carveSection(ctx, f, rng, attempt, sections, route, target, section, targets[section+1:]); err != nil {
+			return core.Route{}, err
 		}
 	}
-	*routes = (*routes)[:idx]
+
+	return route, nil
 }
 
-// Generate routes for empty labyrinth with defined start and finish cells
-func generateRoutes(f *core.Field) error {
-	safety_counter := uint(0)
-	max_route_builds := f.Size()
-	routes := make([]core.Route, 1, max_route_builds)
-	routes[0] = core.Route{}
-	routes[0].Init(f.Start)
-	finish_reached := false
+// Branch a new route off one of the grown ones and continue it until it gets stuck or reaches the finish
+// The frontier takes the new route in, returns false if none of the routes can be branched off anymore
+func growRoute(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt, frontier *routeFrontier, route_number uint) (bool, error) {
+	// pick one of the routes that can still provide new routes and one of its cells to branch off
+	base_route, base_route_split_idx, ok := frontier.pick(rng)
+	if !ok {
+		return false, nil
+	}
 
-	for float64(f.CountCells()[core.Empty])/float64(f.Size())*100 > f.Configuration.Builder.MaxAreaToCoverWithWalls && safety_counter < max_route_builds {
-		// update BaseIndices for all routes to show which route parts can be bases for new routes
-		processRoutesForBaseCompatibility(f, &routes, finish_reached)
-		// remove routes that cannot provide any new routes
-		removeNonBaseRoutes(&routes)
+	// kick off a new route from the chosen base, the cells before it stay with the base route
+	new_route_base, err := base_route.Branch(base_route_split_idx)
+	if err != nil {
+		return false, err
+	}
 
-		if len(routes) == 0 {
-			return f.Error(fmt.Sprintf("Cannot form new routes but area is not filled yet (empty area=%v%%)", float64(f.CountCells()[core.Empty])/float64(f.Size())*100))
+	attempt.Report(Progress{Kind: RouteStarted, Coords: new_route_base.End.Coords, Route: route_number})
+	new_route, err := reachFinishOrLoop(ctx, f, rng, attempt, frontier.sections, new_route_base, route_number, frontier.finish_blocking)
+	if err != nil {
+		return false, err
+	}
+	carved := routeCells(new_route, new_route_base.Length)
+	for _, coords := range carved {
+		if cell, _ := f.At(coords); cell == core.Path {
+			frontier.empty_cells--
 		}
+	}
+	finish_blocking := frontier.finish_blocking
+	if end_cell, err := f.At(new_route.End.Coords); err != nil {
+		return false, err
+	} else if end_cell == core.Finish {
+		frontier.finish_reached = true
+		// finish blocks new routes only if there should be one path near it
+		finish_blocking = f.Configuration.Builder.OnlyOnePathNearFinish
+	}
+
+	frontier.update(carved)
+	frontier.setFinishBlocking(finish_blocking)
+	frontier.add(new_route)
 
-		// pick one of the routes
-		base_route := routes[rand.Intn(len(routes))]
+	return true, nil
+}
 
-		// create a copy until one of the possible bases and kick off a new route
-		base_route_split_idx := base_route.BaseIndices[rand.Intn(len(base_route.BaseIndices))] // pick random route that has possible bases
-		new_route_base, err := base_route.CopyUntil(uint(base_route_split_idx) + 1)
+// Generate routes for empty labyrinth with defined start and finish cells
+// If there are checkpoints, the route through them is built first and the finish cannot be reached by any other route
+// The finish is reached as soon as any route ends in it, only one path near finish only decides whether it blocks the routes after that
+// Returns the statistics of the grown routes
+func generateRoutes(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) (routesSummary, error) {
+	safety_counter := uint(0)
+	max_route_builds := f.Size()
+	first_route := core.Route{}
+	first_route.Init(f.Start)
+	finish_reached, finish_blocking := false, false
+	// usable cells cut off from the start by the mask stay empty forever, so they are left out of the area
+	reachable_area := uint(0)
+	for _, distance := range distancesFrom(f, f.Start, f.IsUsable) {
+		if distance != -1 {
+			reachable_area++
+		}
+	}
+	unreachable_area := f.UsableSize() - reachable_area
+
+	sections := newRouteSections(f)
+	if len(f.Checkpoints) > 0 {
+		main_route, err := reachCheckpoints(ctx, f, rng, attempt, sections, first_route)
 		if err != nil {
-			return err
+			return routesSummary{}, err
 		}
+		first_route, finish_reached, finish_blocking = main_route, true, true
+		safety_counter++
+	}
 
-		new_route, err := reachFinishOrLoop(f, new_route_base, finish_reached)
+	frontier := newRouteFrontier(f, attempt.generation.checker, sections, first_route, finish_reached, finish_blocking)
+	emptyArea := func() float64 {
+		return float64(frontier.empty_cells-unreachable_area) / float64(reachable_area) * 100
+	}
+
+	for emptyArea() > f.Configuration.Builder.MaxAreaToCoverWithWalls && safety_counter < max_route_builds {
+		if err := ctx.Err(); err != nil {
+			return routesSummary{}, err
+		}
+		grown, err := growRoute(ctx, f, rng, attempt, frontier, safety_counter+1)
 		if err != nil {
-			return err
+			return routesSummary{}, err
 		}
-		if end_cell, err := f.At(new_route.End.Coords); err != nil {
-			return err
-		} else if f.Configuration.Builder.OnlyOnePathNearFinish && end_cell == core.Finish {
-			finish_reached = true
+		if !grown {
+			return routesSummary{}, f.Error(fmt.Sprintf("Cannot form new routes but area is not filled yet (empty area=%v%%)", emptyArea()))
 		}
-
-		routes = append(routes, new_route)
 		safety_counter++
 	}
-	if !finish_reached {
-		return f.Error("Area filled but finish was not reached")
+	if !frontier.finish_reached {
+		return routesSummary{}, f.Error("Area filled but finish was not reached")
 	}
 	if safety_counter == max_route_builds {
-		fmt.Println("Safety limit exceeded in routes generator")
-	} else {
-		fmt.Printf("Area filled! \ncells=%v \nempty area=%v\n", f.CountCells(), float32(f.CountCells()[core.Empty])/float32(f.Size())*100)
+		attempt.logf("Safety limit exceeded in routes generator")
+	} else if attempt.logging() {
+		attempt.logf("Area filled! cells=%v empty area=%v", f.CountCells(), float32(emptyArea()))
 	}
 
-	return nil
+	return routesSummary{routes: safety_counter, empty_area: emptyArea(), safety_limit_hit: safety_counter == max_route_builds}, nil
+}
+
+// Carve the labyrinth in a single attempt, then braid it, scatter terrain and place keys and doors according to configuration
+// All of them use the random source of the attempt, so that a failure of any of them only costs this attempt
+func runAttempt(ctx context.Context, f *core.Field, rng *rand.Rand, generator Generator, attempt *Attempt) error {
+	if err := generator.Generate(ctx, f, rng, attempt); err != nil {
+		return err
+	}
+	if err := braidDeadEnds(f, rng); err != nil {
+		return err
+	}
+	scatterTerrain(f, rng)
+
+	return placeKeysAndDoors(ctx, f, rng)
+}
+
+// Run the attempts one after another on the field, emptying it between them
+// Returns the last attempt made and its error
+func generateSequentially(ctx context.Context, f *core.Field, rng *rand.Rand, gen *generation, generator Generator, last_attempt uint) (*Attempt, error) {
+	attempt := gen.attempt(last_attempt + 1)
+	f.MakeEmpty(true)
+	err := runAttempt(ctx, f, rng, generator, attempt)
+	for safety_counter := uint(0); err != nil && !isContextError(err) && safety_counter < f.Configuration.Builder.LabyrinthBuilderAtempts; safety_counter++ {
+		f.MakeEmpty(true)
+		attempt = gen.attempt(attempt.Number + 1)
+		attempt.Report(Progress{Kind: AttemptRestarted, Coords: f.Start, Reason: err.Error()})
+		err = runAttempt(ctx, f, rng, generator, attempt)
+	}
+
+	return attempt, err
+}
+
+// Generate the labyrinth, retrying up to the configured amount of times if the generator fails
+// Attempts run in parallel on copies of the field if configuration allows more than one of them at once
+// Attempts are numbered after the last one, which is 0 for the first call, returns the last attempt made
+func generateWithAttempts(ctx context.Context, f *core.Field, rng *rand.Rand, gen *generation, generator Generator, last_attempt uint) (*Attempt, error) {
+	generate := generateSequentially
+	if f.Configuration.Builder.ParallelAttempts > 1 {
+		generate = generateInParallel
+	}
+	attempt, err := generate(ctx, f, rng, gen, generator, last_attempt)
+
+	if isContextError(err) {
+		f.MakeEmpty(true)
+		return attempt, err
+	}
+	if err != nil {
+		return attempt, f.Error("Safety limit exceeded in labyrinth generator")
+	}
+
+	return attempt, nil
+}
+
+// Check that the error comes from a cancelled context or an exceeded deadline
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Optional parameter of labyrinth generation
+type Option func(*settings)
+
+// Parameters of labyrinth generation collected from options
+type settings struct {
+	seed   *int64
+	source rand.Source
+	hook   Hook
+	logger Logger
+}
+
+// Use the chosen seed instead of the one from configuration
+func WithSeed(seed int64) Option {
+	return func(s *settings) {
+		s.seed = &seed
+	}
+}
+
+// Use the chosen random source instead of one made from a seed, e.g. to make the generation deterministic in its own way
+// It takes priority over the seed, which is recorded in the field as 0 since the source has none
+// The source is only used by the generation it is given to, so it does not have to be safe for concurrent use
+func WithSource(source rand.Source) Option {
+	return func(s *settings) {
+		s.source = source
+	}
+}
+
+// Create the random source of the generation and tell its seed, a source from the options has the seed of 0
+func (s settings) newRand(f *core.Field) (*rand.Rand, int64) {
+	if s.source != nil {
+		return rand.New(s.source), 0
+	}
+	seed := s.pickSeed(f)
+
+	return rand.New(rand.NewSource(seed)), seed
+}
+
+// Pick the seed for the random source, options take priority over configuration
+// A new seed is made up if neither of them has it
+func (s settings) pickSeed(f *core.Field) int64 {
+	switch {
+	case s.seed != nil:
+		return *s.seed
+	case f.Configuration.Builder.Seed != 0:
+		return f.Configuration.Builder.Seed
+	default:
+		return time.Now().UnixNano()
+	}
 }
 
 // Generate labyrinth based on configuration parameters
-func GenerateLabyrinth(f *core.Field) error {
+// Start and finish set on the field are used unless configuration chooses a placement mode for them
+// The seed of the random source is recorded in the field, the same seed and configuration always give the same labyrinth
+// If difficulty ranges are configured, labyrinths are regenerated until one of them is within the ranges,
+// otherwise DifficultyError is returned and the field keeps the labyrinth that came closest to them
+// The report tells how the generation went, it is filled in as far as the generation got even if an error is returned
+func GenerateLabyrinth(f *core.Field, options ...Option) (GenerationReport, error) {
+	return GenerateLabyrinthContext(context.Background(), f, options...)
+}
+
+// Generate labyrinth based on configuration parameters, stopping as soon as the context is done
+// A stopped generation leaves the field empty with only start and finish set and returns the context error
+func GenerateLabyrinthContext(ctx context.Context, f *core.Field, options ...Option) (GenerationReport, error) {
+	started := time.Now()
 	if f.Configuration == nil {
-		return f.Error("Configuration was not initialized yet")
+		return GenerationReport{}, f.Error("Configuration was not initialized yet")
+	}
+	placement := f.Configuration.Builder.Placement
+	if placement == "" && (!f.Start.IsValid(f.Width-1, f.Length-1) || !f.Finish.IsValid(f.Width-1, f.Length-1)) {
+		return GenerationReport{}, f.Error("Start and/or finish are out of bounds or not set yet")
+	}
+	if placement == "farthest-pair" && len(f.Checkpoints) > 0 {
+		return GenerationReport{}, fmt.Errorf("builder error: checkpoints cannot be used with the farthest-pair placement, it moves start and finish")
 	}
-	if !f.Start.IsValid(f.Width-1, f.Length-1) || !f.Finish.IsValid(f.Width-1, f.Length-1) {
-		return f.Error("Start and/or finish are out of bounds or not set yet")
+	if placement == "farthest-pair" && f.Configuration.Puzzle.KeysAndDoors > 0 {
+		return GenerationReport{}, fmt.Errorf("builder error: keys and doors cannot be used with the farthest-pair placement, it moves start and finish away from the doors")
+	}
+	if f.Configuration.Difficulty.IsSet() && f.Configuration.Difficulty.Attempts == 0 {
+		return GenerationReport{}, fmt.Errorf("builder error: difficulty attempts should be positive if any difficulty range is set")
+	}
+	// a mask described in configuration replaces the one set on the field
+	mask, err := f.Configuration.Mask.Build(f.Width, f.Length)
+	if err != nil {
+		return GenerationReport{}, err
+	}
+	generator, err := GeneratorByName(f.Configuration.Builder.Algorithm)
+	if err != nil {
+		return GenerationReport{}, err
+	}
+	if _, is_route_growing := generator.(RouteGrowing); len(f.Checkpoints) > 0 && !is_route_growing {
+		return GenerationReport{}, fmt.Errorf("builder error: checkpoints are only supported by the route-growing algorithm")
+	}
+	checker, err := configuredChecker(f)
+	if err != nil {
+		return GenerationReport{}, err
 	}
 
-	err := generateRoutes(f)
-	safety_counter := uint(0)
-	for ; err != nil && safety_counter < f.Configuration.Builder.LabyrinthBuilderAtempts; safety_counter++ {
-		f.MakeEmpty(true)
-		err = generateRoutes(f)
+	// the field is only changed once everything is validated, and it is given back as it was if the mask, start or finish cannot be set
+	original := f.Copy()
+	reject := func(err error) (GenerationReport, error) {
+		*f = original
+		return GenerationReport{}, err
+	}
+	if placement != "" {
+		// start and finish are placed again, so the previous ones should not stop the mask from being set
+		f.ClearStartAndFinish()
 	}
+	if mask != nil {
+		if err := f.SetMask(mask); err != nil {
+			return reject(err)
+		}
+	}
+
+	var s settings
+	for _, option := range options {
+		option(&s)
+	}
+	rng, seed := s.newRand(f)
+	f.Seed = seed
 
-	if safety_counter == f.Configuration.Builder.LabyrinthBuilderAtempts {
-		return f.Error("Safety limit exceeded in labyrinth generator")
+	if err := placeStartAndFinish(f, rng); err != nil {
+		return reject(err)
 	}
+	if !f.IsUsable(f.Start) || !f.IsUsable(f.Finish) {
+		return reject(f.Error("Start and/or finish are masked out"))
+	}
+
+	gen := &generation{hook: s.hook, logger: s.logger, checker: checker}
 
-	f.FillEmptyCellsWithWalls()
+	attempt, err := generateToDifficulty(ctx, f, rng, gen, generator)
+	// the farthest pair is placed once, so that every labyrinth tried for the difficulty is carved from the same start
+	var difficulty_err DifficultyError
+	if placement == "farthest-pair" && (err == nil || errors.As(err, &difficulty_err)) {
+		placeFarthestPair(f)
+	}
+	if err == nil || errors.As(err, &difficulty_err) {
+		attempt.Report(Progress{Kind: AttemptTaken, Coords: f.Start})
+	}
 
-	return nil
+	return GenerationReport{
+		Attempts:       gen.started,
+		Routes:         attempt.routes.routes,
+		Cells:          f.CountCells(),
+		EmptyArea:      attempt.routes.empty_area,
+		SafetyLimitHit: attempt.routes.safety_limit_hit,
+		Duration:       time.Since(started),
+	}, err
 }
@@ -0,0 +1,211 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Create a field with the default configuration, start in the bottom left corner and finish in the top right one
+func newTestField(t testing.TB, width, length uint) *core.Field {
+	t.Helper()
+	var f core.Field
+	if err := f.Init("../config.toml"); err != nil {
+		t.Fatal(err)
+	}
+	f.SetSize(width, length)
+	f.SetStartAndFinish(core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: int(width) - 1, Y: int(length) - 1})
+
+	return &f
+}
+
+// Check that the finish can be walked to from the start
+func reachesFinish(f *core.Field) bool {
+	return shortestPath(f, f.Start, f.Finish, func(c core.Coordinates) bool { return isWalkable(f, c) }) != nil
+}
+
+// Grow routes on square fields of growing size, a single attempt each with the same seed
+func BenchmarkGenerateRoutes(b *testing.B) {
+	for _, size := range []uint{32, 64, 128, 256} {
+		b.Run(fmt.Sprintf("%vx%v", size, size), func(b *testing.B) {
+			f := newTestField(b, size, size)
+			checker, err := configuredChecker(f)
+			if err != nil {
+				b.Fatal(err)
+			}
+			gen := &generation{checker: checker}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				f.MakeEmpty(true)
+				// attempts that fail on larger fields still grow routes until the area is filled, so they are measured just the same
+				generateRoutes(context.Background(), f, rand.New(rand.NewSource(1)), gen.attempt(1))
+			}
+		})
+	}
+}
+
+// Finish is reached by the routes whether or not it blocks the routes grown after it
+func TestFinishReachedWithoutOnlyOnePathNearFinish(t *testing.T) {
+	for _, only_one_path_near_finish := range []bool{true, false} {
+		for seed := int64(0); seed < 5; seed++ {
+			f := newTestField(t, 20, 20)
+			f.Configuration.Builder.OnlyOnePathNearFinish = only_one_path_near_finish
+			if _, err := GenerateLabyrinth(f, WithSeed(seed)); err != nil {
+				t.Errorf("only one path near finish=%v, seed %v: %v", only_one_path_near_finish, seed, err)
+			} else if !reachesFinish(f) {
+				t.Errorf("only one path near finish=%v, seed %v: finish cannot be reached\n%v", only_one_path_near_finish, seed, f)
+			}
+		}
+	}
+}
+
+// Several checkpoints are reached in order by every checker, none of them can be bypassed on the way to the later ones
+func TestCheckpointsReachedInOrder(t *testing.T) {
+	checkpoint_sets := [][]core.Coordinates{
+		{{X: 15, Y: 3}, {X: 4, Y: 16}},
+		{{X: 15, Y: 3}, {X: 4, Y: 16}, {X: 10, Y: 10}},
+		{{X: 5, Y: 5}, {X: 10, Y: 10}, {X: 15, Y: 15}},
+	}
+	for _, checker := range []string{"", "corners", "n-blocks", "n-blocks or 2-close-blocks"} {
+		for _, checkpoints := range checkpoint_sets {
+			for seed := int64(1); seed <= 5; seed++ {
+				f := newTestField(t, 21, 21)
+				f.Configuration.Builder.Checker = checker
+				f.Configuration.Builder.MaxBlocksAround = 3
+				if err := f.SetCheckpoints(checkpoints...); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := GenerateLabyrinth(f, WithSeed(seed)); err != nil {
+					t.Errorf("checker %q, checkpoints %v, seed %v: %v", checker, checkpoints, seed, err)
+					continue
+				}
+				if !reachesFinish(f) {
+					t.Errorf("checker %q, checkpoints %v, seed %v: finish cannot be reached\n%v", checker, checkpoints, seed, f)
+				}
+				for i, checkpoint := range checkpoints {
+					distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return c != checkpoint && isWalkable(f, c) })
+					for _, later := range append(checkpoints[i+1:], f.Finish) {
+						if distances[cellIndex(f, later)] != -1 {
+							t.Errorf("checker %q, checkpoints %v, seed %v: %v is reached around checkpoint %v\n%v", checker, checkpoints, seed, later, checkpoint, f)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Count the moves of the routes carved by the generation that keep their direction and the ones that go horizontally
+// Moves are only counted once the route has carved two cells before them, so that its direction is known
+func countMoves(t *testing.T, f *core.Field, seed int64) (straight, turns, horizontal, vertical int) {
+	t.Helper()
+	type route struct{ attempt, number uint }
+	carved := make(map[route][]core.Coordinates)
+	hook := func(p Progress) {
+		if p.Kind != CellCarved {
+			return
+		}
+		r := route{p.Attempt, p.Route}
+		carved[r] = append(carved[r], p.Coords)
+		if cells := carved[r]; len(cells) >= 3 {
+			before, prev, next := cells[len(cells)-3], cells[len(cells)-2], cells[len(cells)-1]
+			if next == (core.Coordinates{X: 2*prev.X - before.X, Y: 2*prev.Y - before.Y}) {
+				straight++
+			} else {
+				turns++
+			}
+			if next.Y == prev.Y {
+				horizontal++
+			} else {
+				vertical++
+			}
+		}
+	}
+	if _, err := GenerateLabyrinth(f, WithSeed(seed), WithHook(hook)); err != nil {
+		t.Fatalf("seed %v: %v", seed, err)
+	}
+
+	return
+}
+
+// Straightness shifts the share of straight moves up when positive and down when negative, which still lets the labyrinth generate
+func TestStraightness(t *testing.T) {
+	straight_shares := make(map[float64]float64)
+	for _, straightness := range []float64{-100, -80, -50, 0, 50, 100} {
+		straight, turns := 0, 0
+		for seed := int64(1); seed <= 5; seed++ {
+			f := newTestField(t, 32, 32)
+			f.Configuration.Builder.Straightness = straightness
+			s, tu, _, _ := countMoves(t, f, seed)
+			straight, turns = straight+s, turns+tu
+			if !reachesFinish(f) {
+				t.Errorf("straightness %v, seed %v: finish cannot be reached\n%v", straightness, seed, f)
+			}
+		}
+		straight_shares[straightness] = float64(straight) / float64(straight+turns)
+	}
+	if straight_shares[100] <= straight_shares[50] || straight_shares[50] <= straight_shares[0] {
+		t.Errorf("positive straightness does not add straight moves: %v", straight_shares)
+	}
+	if straight_shares[-100] >= straight_shares[0] || straight_shares[-50] >= straight_shares[0] {
+		t.Errorf("negative straightness does not add turns: %v", straight_shares)
+	}
+}
+
+// Horizontal bias shifts the share of horizontal moves up when positive and down when negative
+func TestHorizontalBias(t *testing.T) {
+	horizontal_shares := make(map[float64]float64)
+	for _, bias := range []float64{-100, 0, 100} {
+		horizontal, vertical := 0, 0
+		for seed := int64(1); seed <= 5; seed++ {
+			f := newTestField(t, 32, 32)
+			f.Configuration.Builder.HorizontalBias = bias
+			_, _, h, v := countMoves(t, f, seed)
+			horizontal, vertical = horizontal+h, vertical+v
+		}
+		horizontal_shares[bias] = float64(horizontal) / float64(horizontal+vertical)
+	}
+	if horizontal_shares[100] <= horizontal_shares[0] || horizontal_shares[0] <= horizontal_shares[-100] {
+		t.Errorf("horizontal bias does not change the share of horizontal moves: %v", horizontal_shares)
+	}
+}
+
+// Random source that counts the numbers taken from it
+type countingSource struct {
+	rand.Source
+	taken int
+}
+
+// Take the next number from the wrapped source
+func (s *countingSource) Int63() int64 {
+	s.taken++
+	return s.Source.Int63()
+}
+
+// Random source given in options is used instead of the seed, the same source gives the same labyrinth as its seed would
+func TestWithSource(t *testing.T) {
+	by_seed := newTestField(t, 20, 20)
+	if _, err := GenerateLabyrinth(by_seed, WithSeed(7)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		f := newTestField(t, 20, 20)
+		source := &countingSource{Source: rand.NewSource(7)}
+		if _, err := GenerateLabyrinth(f, WithSeed(1), WithSource(source)); err != nil {
+			t.Fatal(err)
+		}
+		if source.taken == 0 {
+			t.Error("no numbers were taken from the source")
+		}
+		if f.Seed != 0 {
+			t.Errorf("field records the seed %v for a labyrinth made with a source", f.Seed)
+		}
+		if f.String() != by_seed.String() {
+			t.Errorf("source of the seed 7 gave\n%v\nthe seed 7 gave\n%v", f, by_seed)
+		}
+	}
+}
@@ -1,11 +1,26 @@
 package builder
 
 import (
+	"fmt"
+
 	core "github.com/Via-R/labyrinth-go/core"
 )
 
+// Rule that decides whether the cell at the chosen coordinates can become a part of the route
 type ChoiceChecker func(f *core.Field, coords core.Coordinates, finish_reached bool) bool
 
+// Parameters of the choice checkers taken from configuration
+type CheckerParameters struct {
+	MaxBlocksAround uint // most blocking cells allowed around the choice by the 'n-blocks' checker
+}
+
+// Choice checkers available by their names in configuration
+var choiceCheckers = map[string]func(p CheckerParameters) ChoiceChecker{
+	"corners":        func(CheckerParameters) ChoiceChecker { return isChoiceValidByCornersGetter() },
+	"n-blocks":       func(p CheckerParameters) ChoiceChecker { return isChoiceValidByNBlocksGetter(p.MaxBlocksAround) },
+	"2-close-blocks": func(CheckerParameters) ChoiceChecker { return isChoiceValidBy2CloseBlocksGetter() },
+}
+
 // Check that the Moore's neighborhood of the cell at the chosen coordinates doesn't have any corners made of 3 blocking cells
 func isChoiceValidByCornersGetter() ChoiceChecker {
 	return func(f *core.Field, coords core.Coordinates, finish_reached bool) bool {
@@ -16,7 +31,7 @@ func isChoiceValidByCornersGetter() ChoiceChecker {
 			thirds_counter++
 			shift := loopedMooreShifts[i]
 			choice := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
-			cell, err := f.At(choice)
+			cell, err := f.UsableAt(choice)
 
 			if err == nil && finish_reached && cell == core.Finish {
 				// if we want only one path near finish, we eliminate choices that are in moore's neighborhood with 'Finish' cell
@@ -45,7 +60,7 @@ func isChoiceValidByNBlocksGetter(max_blocks_around uint) ChoiceChecker {
 		blocks_around := uint(0)
 		for _, shift := range MooreShifts {
 			choice := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
-			cell, err := f.At(choice)
+			cell, err := f.UsableAt(choice)
 
 			if err == nil && finish_reached && cell == core.Finish {
 				// if we want only one path near finish, we eliminate choices that are in moore's neighborhood with 'Finish' cell
@@ -70,7 +85,7 @@ func isChoiceValidBy2CloseBlocksGetter() ChoiceChecker {
 		var first_block *core.Coordinates = nil
 		for _, shift := range MooreShifts {
 			choice := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
-			cell, err := f.At(choice)
+			cell, err := f.UsableAt(choice)
 
 			if err == nil && finish_reached && cell == core.Finish {
 				// if we want only one path near finish, we eliminate choices that are in moore's neighborhood with 'Finish' cell
@@ -92,3 +107,83 @@ func isChoiceValidBy2CloseBlocksGetter() ChoiceChecker {
 		return true
 	}
 }
+
+// Combine the checkers so that the choice is valid only if every one of them accepts it
+func AllOf(checkers ...ChoiceChecker) ChoiceChecker {
+	return func(f *core.Field, coords core.Coordinates, finish_reached bool) bool {
+		for _, checker := range checkers {
+			if !checker(f, coords, finish_reached) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Combine the checkers so that the choice is valid if at least one of them accepts it
+func AnyOf(checkers ...ChoiceChecker) ChoiceChecker {
+	return func(f *core.Field, coords core.Coordinates, finish_reached bool) bool {
+		for _, checker := range checkers {
+			if checker(f, coords, finish_reached) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Invert the checker so that the choice is valid only if the checker rejects it
+func Not(checker ChoiceChecker) ChoiceChecker {
+	return func(f *core.Field, coords core.Coordinates, finish_reached bool) bool {
+		return !checker(f, coords, finish_reached)
+	}
+}
+
+// Build the choice checker from the parsed expression, every operator combines the checkers of its operands
+func checkerFromExpression(expression core.CheckerExpression, params CheckerParameters) (ChoiceChecker, error) {
+	checkers := make([]ChoiceChecker, len(expression.Operands))
+	for i, operand := range expression.Operands {
+		checker, err := checkerFromExpression(operand, params)
+		if err != nil {
+			return nil, err
+		}
+		checkers[i] = checker
+	}
+
+	switch expression.Operator {
+	case "and":
+		return AllOf(checkers...), nil
+	case "or":
+		return AnyOf(checkers...), nil
+	case "not":
+		return Not(checkers[0]), nil
+	}
+	getter, ok := choiceCheckers[expression.Name]
+	if !ok {
+		return nil, fmt.Errorf("builder error: unknown choice checker '%v'", expression.Name)
+	}
+
+	return getter(params), nil
+}
+
+// Build the choice checker from the expression of checker names joined with 'and', 'or', 'not' and parentheses,
+// e.g. "corners and not (n-blocks or 2-close-blocks)". The '2-close-blocks' checker is used if the expression is empty
+// The 'n-blocks' checker needs at least one blocking cell allowed around the choice, with none of them it rejects every cell
+func ChoiceCheckerByExpression(expression string, params CheckerParameters) (ChoiceChecker, error) {
+	parsed, err := core.ParseCheckerExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Uses("n-blocks") && params.MaxBlocksAround == 0 {
+		return nil, fmt.Errorf("builder error: max blocks around should be positive for the 'n-blocks' checker")
+	}
+
+	return checkerFromExpression(parsed, params)
+}
+
+// Build the choice checker described in the configuration of the field
+func configuredChecker(f *core.Field) (ChoiceChecker, error) {
+	return ChoiceCheckerByExpression(f.Configuration.Builder.Checker, CheckerParameters{MaxBlocksAround: f.Configuration.Builder.MaxBlocksAround})
+}
@@ -0,0 +1,49 @@
+package builder
+
+import "testing"
+
+// Configured checker expressions either build a labyrinth or stop the generation before it starts
+func TestConfiguredChecker(t *testing.T) {
+	cases := []struct {
+		expression string
+		valid      bool
+	}{
+		{"", true},
+		{"corners", true},
+		{"n-blocks or 2-close-blocks", true},
+		{"not (corners) or 2-close-blocks", true},
+		{"unknown", false},
+		{"corners and", false},
+		{"(corners", false},
+	}
+	for _, c := range cases {
+		f := newTestField(t, 15, 15)
+		f.Configuration.Builder.Checker = c.expression
+		report, err := GenerateLabyrinth(f, WithSeed(1))
+		switch {
+		case c.valid && err != nil:
+			t.Errorf("%q: %v", c.expression, err)
+		case c.valid && !reachesFinish(f):
+			t.Errorf("%q: finish cannot be reached\n%v", c.expression, f)
+		case !c.valid && err == nil:
+			t.Errorf("%q: expected an error", c.expression)
+		case !c.valid && report.Attempts != 0:
+			t.Errorf("%q: generation started with an invalid checker", c.expression)
+		}
+	}
+}
+
+// The 'n-blocks' checker without any blocking cells allowed around the choice would reject every cell, so it is not built
+func TestNBlocksNeedsBlocksAround(t *testing.T) {
+	for _, expression := range []string{"n-blocks", "corners or not n-blocks"} {
+		if _, err := ChoiceCheckerByExpression(expression, CheckerParameters{MaxBlocksAround: 0}); err == nil {
+			t.Errorf("%q: expected an error", expression)
+		}
+		if _, err := ChoiceCheckerByExpression(expression, CheckerParameters{MaxBlocksAround: 3}); err != nil {
+			t.Errorf("%q: %v", expression, err)
+		}
+	}
+	if _, err := ChoiceCheckerByExpression("corners", CheckerParameters{MaxBlocksAround: 0}); err != nil {
+		t.Errorf("corners: %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// How much the complexity changes between the attempts to reach the target solution length
+const complexityStep = 10
+
+// Measurements of how hard the labyrinth is to solve
+type Difficulty struct {
+	SolutionLength uint    // amount of steps in the shortest route from start to finish
+	DeadEnds       uint    // walkable cells with only one way out, start and finish are not counted
+	Branching      float64 // average amount of ways to go on from each cell of the shortest route
+}
+
+// Error returned when none of the generated labyrinths fell within the difficulty ranges
+type DifficultyError struct {
+	Attempts   uint       // amount of labyrinths generated while looking for the target
+	Closest    Difficulty // measurements of the labyrinth that came closest to the target
+	Misses     []string   // ranges the closest labyrinth fell out of
+	Complexity float64    // complexity used for the closest labyrinth
+}
+
+// String representation of the difficulty error
+func (e DifficultyError) Error() string {
+	return fmt.Sprintf("builder error: target difficulty was not reached in %v attempts, closest labyrinth has %v (complexity=%v)", e.Attempts, strings.Join(e.Misses, ", "), e.Complexity)
+}
+
+// Count walkable cells next to the chosen coordinates
+func countWalkableNeighbors(f *core.Field, coords core.Coordinates) int {
+	counter := 0
+	for _, shift := range NeumannShifts {
+		if isWalkable(f, core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}) {
+			counter++
+		}
+	}
+
+	return counter
+}
+
+// Check that the cell can be walked through by someone who has keys of all colours
+func isOpen(f *core.Field, coords core.Coordinates) bool {
+	return canEnterWithKeys(f, coords, core.KeyColours)
+}
+
+// Count cells next to the chosen coordinates that can be walked through by someone who has keys of all colours
+func countOpenNeighbors(f *core.Field, coords core.Coordinates) int {
+	counter := 0
+	for _, shift := range NeumannShifts {
+		if isOpen(f, core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}) {
+			counter++
+		}
+	}
+
+	return counter
+}
+
+// Measure how hard the labyrinth is to solve, keys are not taken into account and doors are treated as open
+func MeasureDifficulty(f *core.Field) (Difficulty, error) {
+	path := shortestPath(f, f.Start, f.Finish, func(c core.Coordinates) bool { return isOpen(f, c) })
+	if path == nil {
+		return Difficulty{}, f.Error("Finish cannot be reached from start, difficulty cannot be measured")
+	}
+
+	d := Difficulty{SolutionLength: uint(len(path) - 1)}
+	for y := 0; y < int(f.Length); y++ {
+		for x := 0; x < int(f.Width); x++ {
+			coords := core.Coordinates{X: x, Y: y}
+			if coords != f.Start && coords != f.Finish && isOpen(f, coords) && countOpenNeighbors(f, coords) == 1 {
+				d.DeadEnds++
+			}
+		}
+	}
+
+	ways := 0
+	for i, coords := range path[:len(path)-1] {
+		ways += countOpenNeighbors(f, coords)
+		if i > 0 {
+			// the way back is not a way to go on
+			ways--
+		}
+	}
+	if d.SolutionLength > 0 {
+		d.Branching = float64(ways) / float64(d.SolutionLength)
+	}
+
+	return d, nil
+}
+
+// Measure the difficulty of the labyrinth the way it is going to be solved, with the farthest pair placed if configuration asks for it
+func measureFinalDifficulty(f *core.Field) (Difficulty, error) {
+	if f.Configuration.Builder.Placement != "farthest-pair" {
+		return MeasureDifficulty(f)
+	}
+	placed := f.Copy()
+	placeFarthestPair(&placed)
+
+	return MeasureDifficulty(&placed)
+}
+
+// Convert the upper bound from configuration, nil stays nil as there is no bound
+func floatBound(bound *uint) *float64 {
+	if bound == nil {
+		return nil
+	}
+	value := float64(*bound)
+
+	return &value
+}
+
+// Find how far the value is from the range relative to its closest bound, 0 if it is within the range
+// The maximum is nil if there is no upper bound
+func rangeDeviation(value, min float64, max *float64) float64 {
+	switch {
+	case value < min:
+		return (min - value) / min
+	case max != nil && value > *max:
+		// going over the maximum of 0 is measured against 1, so that the deviation stays finite
+		if *max == 0 {
+			return value
+		}
+		return (value - *max) / *max
+	default:
+		return 0
+	}
+}
+
+// Describe the range, the maximum is nil if there is no upper bound
+func describeRange(min float64, max *float64) string {
+	switch {
+	case max == nil:
+		return fmt.Sprintf("at least %v", min)
+	case min == 0:
+		return fmt.Sprintf("at most %v", *max)
+	default:
+		return fmt.Sprintf("%v..%v", min, *max)
+	}
+}
+
+// Describe every difficulty range from configuration that the measurements fell out of
+// Returns the descriptions and the overall deviation from the ranges, which is 0 if all of them are met
+func missedDifficulty(f *core.Field, d Difficulty) ([]string, float64) {
+	target := f.Configuration.Difficulty
+	misses, deviation := make([]string, 0), 0.
+	checks := []struct {
+		name          string
+		value         float64
+		min           float64
+		max           *float64
+		value_to_show string
+	}{
+		{"solution length", float64(d.SolutionLength), float64(target.MinSolutionLength), floatBound(target.MaxSolutionLength), fmt.Sprint(d.SolutionLength)},
+		{"dead ends", float64(d.DeadEnds), float64(target.MinDeadEnds), floatBound(target.MaxDeadEnds), fmt.Sprint(d.DeadEnds)},
+		{"branching", d.Branching, target.MinBranching, target.MaxBranching, fmt.Sprintf("%.2f", d.Branching)},
+	}
+	for _, check := range checks {
+		if check_deviation := rangeDeviation(check.value, check.min, check.max); check_deviation > 0 {
+			misses = append(misses, fmt.Sprintf("%v=%v (wanted %v)", check.name, check.value_to_show, describeRange(check.min, check.max)))
+			deviation += check_deviation
+		}
+	}
+
+	return misses, deviation
+}
+
+// Generate labyrinths until one of them falls within the difficulty ranges from configuration
+// Route-growing complexity is tuned towards the target solution length on a private copy of configuration,
+// so the shared configuration stays untouched. If the target is not reached, the field gets the closest labyrinth back
+// Rounds that fail to generate a labyrinth count as misses, their error is returned only if no round succeeded
+// Returns the attempt that made the labyrinth left on the field
+func generateToDifficulty(ctx context.Context, f *core.Field, rng *rand.Rand, gen *generation, generator Generator) (*Attempt, error) {
+	if !f.Configuration.Difficulty.IsSet() {
+		return generateWithAttempts(ctx, f, rng, gen, generator, 0)
+	}
+
+	shared_configuration := f.Configuration
+	tuned_configuration := *shared_configuration
+	f.Configuration = &tuned_configuration
+	defer func() { f.Configuration = shared_configuration }()
+
+	target := tuned_configuration.Difficulty
+	closest, closest_deviation := DifficultyError{}, math.Inf(1)
+	var closest_field core.Field
+	var closest_attempt *Attempt
+	var last_err error
+	attempt, last_misses := gen.attempt(0), []string{}
+	for round := uint(0); round < target.Attempts; round++ {
+		if round > 0 {
+			gen.attempt(attempt.Number + 1).Report(Progress{Kind: AttemptRestarted, Coords: f.Start, Reason: strings.Join(last_misses, ", ")})
+		}
+
+		var err error
+		var measured Difficulty
+		if attempt, err = generateWithAttempts(ctx, f, rng, gen, generator, attempt.Number); err == nil {
+			measured, err = measureFinalDifficulty(f)
+		}
+		if isContextError(err) {
+			if closest_attempt != nil {
+				*f = closest_field
+			}
+			return attempt, err
+		}
+		if err != nil {
+			last_err, last_misses = err, []string{err.Error()}
+			continue
+		}
+		misses, deviation := missedDifficulty(f, measured)
+		if len(misses) == 0 {
+			return attempt, nil
+		}
+		last_misses = misses
+		if deviation < closest_deviation {
+			closest, closest_deviation = DifficultyError{Closest: measured, Misses: misses, Complexity: tuned_configuration.Builder.Complexity}, deviation
+			closest_field, closest_attempt = f.Copy(), attempt
+		}
+
+		// complex routes wander away from the finish, so they make the solution longer
+		switch {
+		case measured.SolutionLength < target.MinSolutionLength:
+			tuned_configuration.Builder.Complexity = math.Min(100, tuned_configuration.Builder.Complexity+complexityStep)
+		case target.MaxSolutionLength != nil && measured.SolutionLength > *target.MaxSolutionLength:
+			tuned_configuration.Builder.Complexity = math.Max(0, tuned_configuration.Builder.Complexity-complexityStep)
+		}
+	}
+	if closest_attempt == nil {
+		return attempt, last_err
+	}
+	*f = closest_field
+	closest.Attempts = target.Attempts
+
+	return closest_attempt, closest
+}
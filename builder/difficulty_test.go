@@ -0,0 +1,135 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Ranges without the upper bound are only limited from below, the maximum of 0 is a limit
+func TestRangeDeviation(t *testing.T) {
+	zero, ten := 0., 10.
+	cases := []struct {
+		value, min float64
+		max        *float64
+		deviation  float64
+	}{
+		{5, 0, nil, 0},
+		{5, 10, nil, 0.5},
+		{5, 0, &ten, 0},
+		{15, 0, &ten, 0.5},
+		{0, 0, &zero, 0},
+		{3, 0, &zero, 3},
+	}
+	for _, c := range cases {
+		if deviation := rangeDeviation(c.value, c.min, c.max); deviation != c.deviation {
+			t.Errorf("value %v, min %v, max %v: expected deviation %v, got %v", c.value, c.min, c.max, c.deviation, deviation)
+		}
+	}
+}
+
+// Labyrinth can be required to have no dead ends at all
+func TestDifficultyWithoutDeadEnds(t *testing.T) {
+	f := newTestField(t, 15, 15)
+	f.Configuration.Braid.Percentage = 100
+	no_dead_ends := uint(0)
+	f.Configuration.Difficulty.MaxDeadEnds = &no_dead_ends
+	f.Configuration.Difficulty.Attempts = 20
+	if _, err := GenerateLabyrinth(f, WithSeed(1)); err != nil {
+		t.Fatal(err)
+	}
+	if measured, _ := MeasureDifficulty(f); measured.DeadEnds != 0 {
+		t.Errorf("labyrinth has %v dead ends", measured.DeadEnds)
+	}
+}
+
+// When the target is missed, the field keeps the closest labyrinth the error describes
+func TestDifficultyErrorDescribesField(t *testing.T) {
+	for seed := int64(0); seed < 3; seed++ {
+		f := newTestField(t, 15, 15)
+		f.Configuration.Difficulty.MinSolutionLength = 1000
+		f.Configuration.Difficulty.Attempts = 4
+		report, err := GenerateLabyrinth(f, WithSeed(seed))
+		var difficulty_err DifficultyError
+		if !errors.As(err, &difficulty_err) {
+			t.Fatalf("seed %v: expected the difficulty error, got %v", seed, err)
+		}
+		if difficulty_err.Attempts != 4 || report.Attempts < 4 {
+			t.Errorf("seed %v: error counts %v rounds and the report %v attempts", seed, difficulty_err.Attempts, report.Attempts)
+		}
+		measured, err := MeasureDifficulty(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if measured != difficulty_err.Closest {
+			t.Errorf("seed %v: the error describes %+v, the field has %+v", seed, difficulty_err.Closest, measured)
+		}
+	}
+}
+
+// Difficulty ranges without any attempts to reach them stop the generation before the field is changed
+func TestDifficultyWithoutAttempts(t *testing.T) {
+	f := newTestField(t, 15, 15)
+	f.Configuration.Difficulty.MinSolutionLength = 10
+	f.Configuration.Difficulty.Attempts = 0
+	report, err := GenerateLabyrinth(f, WithSeed(1))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if report.Attempts != 0 || f.CountCells()[core.Path] != 0 {
+		t.Errorf("generation started without difficulty attempts\n%v", f)
+	}
+}
+
+// Generator that fails on the chosen calls and grows routes on the rest of them
+type failingGenerator struct {
+	calls   *int
+	failing func(call int) bool
+}
+
+func (g failingGenerator) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	*g.calls++
+	if g.failing(*g.calls) {
+		return f.Error("generator failed on purpose")
+	}
+
+	return RouteGrowing{}.Generate(ctx, f, rng, attempt)
+}
+
+// Rounds that fail to generate a labyrinth are missed, the field still gets the closest labyrinth back
+func TestDifficultyRoundFailure(t *testing.T) {
+	f := newTestField(t, 15, 15)
+	f.Configuration.Builder.LabyrinthBuilderAtempts = 0
+	f.Configuration.Difficulty.MinSolutionLength = 1000
+	f.Configuration.Difficulty.Attempts = 4
+	checker, err := configuredChecker(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	generator := failingGenerator{calls: &calls, failing: func(call int) bool { return call > 1 }}
+	_, err = generateToDifficulty(context.Background(), f, rand.New(rand.NewSource(1)), &generation{checker: checker}, generator)
+	var difficulty_err DifficultyError
+	if !errors.As(err, &difficulty_err) {
+		t.Fatalf("expected the difficulty error, got %v", err)
+	}
+	if calls != 4 || difficulty_err.Attempts != 4 {
+		t.Errorf("generator was called %v times, error counts %v rounds, expected 4 of both", calls, difficulty_err.Attempts)
+	}
+	if measured, err := MeasureDifficulty(f); err != nil || measured != difficulty_err.Closest {
+		t.Errorf("the error describes %+v, the field has %+v with error %v", difficulty_err.Closest, measured, err)
+	}
+
+	calls = 0
+	generator.failing = func(int) bool { return true }
+	if _, err = generateToDifficulty(context.Background(), f, rand.New(rand.NewSource(1)), &generation{checker: checker}, generator); err == nil || errors.As(err, &difficulty_err) {
+		t.Errorf("expected the generation error when every round fails, got %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("generator was called %v times, expected 4", calls)
+	}
+}
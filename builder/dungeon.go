@@ -0,0 +1,219 @@
+package builder
+
+import (
+	"context"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Rooms and corridors, places rectangular rooms and joins them with corridors grown by the route-growing algorithm
+// Room count, sizes and the amount of extra doors come from configuration
+type Dungeon struct{}
+
+// Rectangular room of the dungeon, the corner with the lowest coordinates and the size
+type dungeonRoom struct {
+	x, y, width, length int
+}
+
+// Check that the coordinates are within the room, extended by the margin on every side
+func (r dungeonRoom) contains(c core.Coordinates, margin int) bool {
+	return c.X >= r.x-margin && c.X < r.x+r.width+margin && c.Y >= r.y-margin && c.Y < r.y+r.length+margin
+}
+
+// Check that the rooms do not share any cells, the margin is added around the first one
+func (r dungeonRoom) overlaps(other dungeonRoom, margin int) bool {
+	return other.x < r.x+r.width+margin && r.x-margin < other.x+other.width &&
+		other.y < r.y+r.length+margin && r.y-margin < other.y+other.length
+}
+
+// Cells of the room extended by the margin on every side, cells out of the field's bounds are left out
+func (r dungeonRoom) cells(f *core.Field, margin int) []core.Coordinates {
+	cells := make([]core.Coordinates, 0, (r.width+2*margin)*(r.length+2*margin))
+	for y := r.y - margin; y < r.y+r.length+margin; y++ {
+		for x := r.x - margin; x < r.x+r.width+margin; x++ {
+			if c := (core.Coordinates{X: x, Y: y}); c.IsValid(f.Width-1, f.Length-1) {
+				cells = append(cells, c)
+			}
+		}
+	}
+
+	return cells
+}
+
+// Walls around the room which can become doors to the cells outside of it
+func (r dungeonRoom) doors(f *core.Field) []core.Coordinates {
+	doors := make([]core.Coordinates, 0)
+	for _, c := range r.cells(f, 1) {
+		if r.contains(c, 0) {
+			continue
+		}
+		for _, shift := range NeumannShifts {
+			inside := core.Coordinates{X: c.X + shift[0], Y: c.Y + shift[1]}
+			if r.contains(inside, 0) && canKnockThrough(f, c, inside) {
+				doors = append(doors, c)
+				break
+			}
+		}
+	}
+
+	return doors
+}
+
+// Check that corridors going around the rooms can still reach the finish and the checkpoints from the start
+func keepsCorridorsConnected(f *core.Field, rooms []dungeonRoom) bool {
+	distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool {
+		for _, room := range rooms {
+			if room.contains(c, 1) {
+				return false
+			}
+		}
+
+		return f.IsUsable(c)
+	})
+	for _, c := range append([]core.Coordinates{f.Finish}, f.Checkpoints...) {
+		if distances[cellIndex(f, c)] == -1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Place up to the configured amount of rooms at random, rooms keep a wall between each other
+// Rooms only take usable cells reachable from the start, keep away from start, finish and checkpoints and never cut them off from each other
+func placeDungeonRooms(f *core.Field, rng *rand.Rand) []dungeonRoom {
+	settings := f.Configuration.Dungeon
+	rooms := make([]dungeonRoom, 0, settings.Rooms)
+	// rooms in the parts of the field cut off from the start by the mask could never be joined with the corridors
+	reachable := distancesFrom(f, f.Start, f.IsUsable)
+	for tries := uint(0); uint(len(rooms)) < settings.Rooms && tries < settings.Rooms*50; tries++ {
+		width := int(settings.MinRoomSize) + rng.Intn(int(settings.MaxRoomSize-settings.MinRoomSize)+1)
+		length := int(settings.MinRoomSize) + rng.Intn(int(settings.MaxRoomSize-settings.MinRoomSize)+1)
+		if width > int(f.Width) || length > int(f.Length) {
+			continue
+		}
+		room := dungeonRoom{x: rng.Intn(int(f.Width) - width + 1), y: rng.Intn(int(f.Length) - length + 1), width: width, length: length}
+
+		fits := true
+		for _, other := range rooms {
+			fits = fits && !room.overlaps(other, 1)
+		}
+		for _, c := range append([]core.Coordinates{f.Start, f.Finish}, f.Checkpoints...) {
+			fits = fits && !room.contains(c, 1)
+		}
+		for _, c := range room.cells(f, 0) {
+			fits = fits && f.IsUsable(c) && reachable[cellIndex(f, c)] != -1
+		}
+		if fits && keepsCorridorsConnected(f, append(rooms, room)) {
+			rooms = append(rooms, room)
+		}
+	}
+
+	return rooms
+}
+
+// Join every room with the rest of the dungeon and add the configured amount of extra doors
+// Rooms without a wall next to the reachable area get a tunnel to it instead
+func connectDungeonRooms(f *core.Field, rng *rand.Rand, rooms []dungeonRoom) error {
+	connected := make([]bool, len(rooms))
+	for {
+		reachable := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return isWalkable(f, c) })
+		isReachable := func(c core.Coordinates) bool {
+			return c.IsValid(f.Width-1, f.Length-1) && reachable[cellIndex(f, c)] != -1
+		}
+
+		left, joined := 0, false
+		for i, room := range rooms {
+			if connected[i] = connected[i] || isReachable(core.Coordinates{X: room.x, Y: room.y}); connected[i] {
+				continue
+			}
+			left++
+			doors := make([]core.Coordinates, 0)
+			for _, door := range room.doors(f) {
+				for _, shift := range NeumannShifts {
+					if outside := (core.Coordinates{X: door.X + shift[0], Y: door.Y + shift[1]}); !room.contains(outside, 0) && isReachable(outside) {
+						doors = append(doors, door)
+						break
+					}
+				}
+			}
+			if len(doors) > 0 {
+				f.Set(core.Empty, doors[rng.Intn(len(doors))])
+				joined = true
+				break
+			}
+		}
+		if left == 0 {
+			break
+		}
+		if joined {
+			continue
+		}
+
+		// none of the rooms is next to the reachable area, so one of them gets a tunnel through the walls
+		for i, room := range rooms {
+			if connected[i] {
+				continue
+			}
+			tunnel := shortestPath(f, core.Coordinates{X: room.x, Y: room.y}, f.Start, f.IsUsable)
+			if tunnel == nil {
+				return f.Error("Cannot connect the room of the dungeon, it is cut off by the mask")
+			}
+			for _, c := range tunnel {
+				if isReachable(c) {
+					break
+				}
+				f.Set(core.Empty, c)
+			}
+			break
+		}
+	}
+
+	for extra := uint(0); extra < f.Configuration.Dungeon.ExtraConnections && len(rooms) > 0; extra++ {
+		room := rooms[rng.Intn(len(rooms))]
+		if doors := room.doors(f); len(doors) > 0 {
+			f.Set(core.Empty, doors[rng.Intn(len(doors))])
+		}
+	}
+
+	return nil
+}
+
+// Place rooms, grow corridors around them and join them with doors
+// Rooms are masked out while the corridors grow, so routes go around them instead of through them
+func (Dungeon) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	original_mask := f.Mask()
+	rooms := placeDungeonRooms(f, rng)
+	if len(rooms) == 0 {
+		return f.Error("Cannot place any room of the dungeon")
+	}
+
+	corridors_mask := f.Mask()
+	if corridors_mask == nil {
+		corridors_mask = core.NewMask(f.Width, f.Length)
+	}
+	for _, room := range rooms {
+		for _, c := range room.cells(f, 1) {
+			corridors_mask[c.Y][c.X] = false
+		}
+	}
+	if err := f.SetMask(corridors_mask); err != nil {
+		return err
+	}
+	routes, err := generateRoutes(ctx, f, rng, attempt)
+	f.SetMask(original_mask)
+	if err != nil {
+		return err
+	}
+	attempt.routes = routes
+	f.FillEmptyCellsWithWalls()
+
+	for _, room := range rooms {
+		for _, c := range room.cells(f, 0) {
+			f.Set(core.Empty, c)
+		}
+	}
+
+	return connectDungeonRooms(f, rng, rooms)
+}
@@ -0,0 +1,103 @@
+package builder
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Generate the dungeon on the field in a single attempt with the random source of the seed and return its rooms
+// Rooms are placed with the first numbers of the random source, so placing them again from the same seed gives the same ones
+// Single attempts can fail the way they do during the generation, they are tried again with the next seed then
+func generateDungeon(t *testing.T, f *core.Field, seed int64) []dungeonRoom {
+	t.Helper()
+	checker, err := configuredChecker(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for attempt_seed := seed; attempt_seed < seed+10; attempt_seed++ {
+		f.MakeEmpty(true)
+		empty_field := f.Copy()
+		rooms := placeDungeonRooms(&empty_field, rand.New(rand.NewSource(attempt_seed)))
+		if err = (Dungeon{}).Generate(context.Background(), f, rand.New(rand.NewSource(attempt_seed)), (&generation{checker: checker}).attempt(1)); err == nil {
+			return rooms
+		}
+	}
+	t.Fatalf("seed %v: %v", seed, err)
+
+	return nil
+}
+
+// Count the walkable cells right next to the rooms, which are the doors between them and the corridors
+func countDoors(f *core.Field, rooms []dungeonRoom) int {
+	doors := 0
+	for _, room := range rooms {
+		for _, c := range room.cells(f, 1) {
+			if !room.contains(c, 0) && isWalkable(f, c) {
+				doors++
+			}
+		}
+	}
+
+	return doors
+}
+
+// Rooms keep to the configured amount and sizes, keep a wall between each other, stay empty and can all be reached from the start
+func TestDungeonRooms(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		f := newTestField(t, 31, 31)
+		settings := f.Configuration.Dungeon
+		rooms := generateDungeon(t, f, seed)
+		if len(rooms) == 0 || uint(len(rooms)) > settings.Rooms {
+			t.Errorf("seed %v: %v rooms were placed, expected 1 to %v", seed, len(rooms), settings.Rooms)
+		}
+
+		reachable := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return isWalkable(f, c) })
+		for i, room := range rooms {
+			for _, size := range []int{room.width, room.length} {
+				if size < int(settings.MinRoomSize) || size > int(settings.MaxRoomSize) {
+					t.Errorf("seed %v: room %+v is out of the sizes from %v to %v", seed, room, settings.MinRoomSize, settings.MaxRoomSize)
+				}
+			}
+			for _, other := range rooms[i+1:] {
+				if room.overlaps(other, 1) {
+					t.Errorf("seed %v: rooms %+v and %+v have no wall between them", seed, room, other)
+				}
+			}
+			for _, c := range room.cells(f, 0) {
+				if cell, _ := f.At(c); cell != core.Empty || reachable[cellIndex(f, c)] == -1 {
+					t.Errorf("seed %v: room %+v has %v at %v, reachable: %v\n%v", seed, room, cell, c, reachable[cellIndex(f, c)] != -1, f)
+					break
+				}
+			}
+		}
+		if !reachesFinish(f) {
+			t.Errorf("seed %v: finish cannot be reached\n%v", seed, f)
+		}
+	}
+}
+
+// Extra connections only add doors on top of the ones joining the rooms with the rest of the dungeon
+func TestDungeonExtraConnections(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		without_extra := newTestField(t, 31, 31)
+		without_extra.Configuration.Dungeon.ExtraConnections = 0
+		rooms := generateDungeon(t, without_extra, seed)
+
+		with_extra := newTestField(t, 31, 31)
+		with_extra.Configuration.Dungeon.ExtraConnections = 3
+		generateDungeon(t, with_extra, seed)
+
+		for idx := 0; idx < int(with_extra.Size()); idx++ {
+			c := core.Coordinates{X: idx % int(with_extra.Width), Y: idx / int(with_extra.Width)}
+			if isWalkable(without_extra, c) && !isWalkable(with_extra, c) {
+				t.Fatalf("seed %v: extra connections closed %v", seed, c)
+			}
+		}
+		if added := countDoors(with_extra, rooms) - countDoors(without_extra, rooms); added < 1 || added > 3 {
+			t.Errorf("seed %v: extra connections added %v doors, expected 1 to 3", seed, added)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package builder
+
+import (
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// How far from a changed cell the choices of other cells can change, one step to the choice and one to its Moore's neighborhood
+const frontierRadius = 2
+
+// Routes grown so far together with the cells new routes can branch off
+// Only the cells around the carved ones are checked again, so growing a route costs the same on any size of the field
+// Every route only keeps the cells it carved itself, the ones before the cell it branched off belong to the routes it grew from
+type routeFrontier struct {
+	f               *core.Field
+	checker         ChoiceChecker
+	sections        *routeSections // sections between checkpoints, nil if there are none
+	empty_cells     uint           // empty cells are only ever carved, so they are counted once and then kept track of with every route
+	finish_reached  bool
+	finish_blocking bool
+	can_branch      []bool // cells with at least one choice, by cell index
+	owners          []int  // route that carved every cell, by cell index, -1 for the cells no route went through
+	routes          []core.Route
+	bases           []int // amount of cells of every route that can be branched off
+	alive           []int // routes with at least one cell to branch off
+	alive_positions []int // position of every route in the alive ones, -1 if it has no cells to branch off
+	checked         []int // number of the last check of every cell, so that each cell is checked once per update
+	check           int
+}
+
+// Create the frontier of the field with the first route, which should be already carved
+func newRouteFrontier(f *core.Field, checker ChoiceChecker, sections *routeSections, first_route core.Route, finish_reached, finish_blocking bool) *routeFrontier {
+	fr := &routeFrontier{
+		f:               f,
+		checker:         checker,
+		sections:        sections,
+		empty_cells:     f.CountCells()[core.Empty],
+		finish_reached:  finish_reached,
+		finish_blocking: finish_blocking,
+		can_branch:      make([]bool, f.Size()),
+		owners:          make([]int, f.Size()),
+		checked:         make([]int, f.Size()),
+	}
+	for idx := range fr.owners {
+		fr.owners[idx] = -1
+	}
+	fr.update(routeCells(first_route, 0))
+	fr.add(first_route)
+
+	return fr
+}
+
+// Check whether the cell can be branched off and update the amount of bases of the routes going through it
+func (fr *routeFrontier) recheck(coords core.Coordinates) {
+	idx := cellIndex(fr.f, coords)
+	choices, err := findChoices(fr.f, fr.checker, coords, fr.finish_blocking)
+	can_branch := err == nil && len(fr.sections.filter(choices, coords)) > 0
+	if can_branch == fr.can_branch[idx] {
+		return
+	}
+
+	fr.can_branch[idx] = can_branch
+	if route := fr.owners[idx]; route != -1 {
+		if can_branch {
+			fr.bases[route]++
+		} else {
+			fr.bases[route]--
+		}
+		fr.updateAlive(route)
+	}
+}
+
+// Keep the route among the alive ones exactly while it has cells to branch off
+// Routes are swapped with the last alive one on removal, so that it takes the same time however many of them there are
+func (fr *routeFrontier) updateAlive(route int) {
+	position := fr.alive_positions[route]
+	switch {
+	case fr.bases[route] > 0 && position == -1:
+		fr.alive_positions[route] = len(fr.alive)
+		fr.alive = append(fr.alive, route)
+	case fr.bases[route] == 0 && position != -1:
+		last := fr.alive[len(fr.alive)-1]
+		fr.alive[position], fr.alive_positions[last] = last, position
+		fr.alive, fr.alive_positions[route] = fr.alive[:len(fr.alive)-1], -1
+	}
+}
+
+// Check again every cell whose choices might have changed after the chosen cells were carved
+func (fr *routeFrontier) update(changed []core.Coordinates) {
+	fr.check++
+	for _, coords := range changed {
+		for dy := -frontierRadius; dy <= frontierRadius; dy++ {
+			for dx := -frontierRadius; dx <= frontierRadius; dx++ {
+				neighbor := core.Coordinates{X: coords.X + dx, Y: coords.Y + dy}
+				if neighbor.IsValid(fr.f.Width-1, fr.f.Length-1) && fr.checked[cellIndex(fr.f, neighbor)] != fr.check {
+					fr.checked[cellIndex(fr.f, neighbor)] = fr.check
+					fr.recheck(neighbor)
+				}
+			}
+		}
+	}
+}
+
+// Change whether the finish blocks new routes, which changes the choices of cells anywhere on the field
+func (fr *routeFrontier) setFinishBlocking(finish_blocking bool) {
+	if finish_blocking == fr.finish_blocking {
+		return
+	}
+
+	fr.finish_blocking = finish_blocking
+	for idx := range fr.can_branch {
+		fr.recheck(core.Coordinates{X: idx % int(fr.f.Width), Y: idx / int(fr.f.Width)})
+	}
+}
+
+// Add the route, its cells that are new to the frontier should be updated beforehand
+// The route owns the cells no other route went through, the cell it branched off stays with the route it came from
+func (fr *routeFrontier) add(route core.Route) {
+	number := len(fr.routes)
+	fr.routes, fr.bases, fr.alive_positions = append(fr.routes, route), append(fr.bases, 0), append(fr.alive_positions, -1)
+	it := route.GetIterator()
+	for coords, is_end := it(); !is_end; coords, is_end = it() {
+		idx := cellIndex(fr.f, coords)
+		if fr.owners[idx] != -1 {
+			continue
+		}
+		fr.owners[idx] = number
+		if fr.can_branch[idx] {
+			fr.bases[number]++
+		}
+	}
+	fr.updateAlive(number)
+}
+
+// Pick one of the routes that can be branched off and one of its own cells to branch off
+// Returns the route with the position of the cell in it, or false if none of the routes can be branched off
+func (fr *routeFrontier) pick(rng *rand.Rand) (core.Route, uint, bool) {
+	if len(fr.alive) == 0 {
+		return core.Route{}, 0, false
+	}
+
+	route := fr.alive[rng.Intn(len(fr.alive))]
+	base := rng.Intn(fr.bases[route])
+	it := fr.routes[route].GetIterator()
+	position := uint(0)
+	for coords, is_end := it(); !is_end; coords, is_end = it() {
+		if idx := cellIndex(fr.f, coords); fr.owners[idx] == route && fr.can_branch[idx] {
+			if base == 0 {
+				break
+			}
+			base--
+		}
+		position++
+	}
+
+	return fr.routes[route], position, true
+}
+
+// Cells of the route starting from the chosen position
+func routeCells(route core.Route, from uint) []core.Coordinates {
+	cells := make([]core.Coordinates, 0, route.Length-from)
+	it := route.GetIterator()
+	position := uint(0)
+	for coords, is_end := it(); !is_end; coords, is_end = it() {
+		if position >= from {
+			cells = append(cells, coords)
+		}
+		position++
+	}
+
+	return cells
+}
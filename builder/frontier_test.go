@@ -0,0 +1,80 @@
+package builder
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Compare everything the frontier keeps track of with a full recount of the field
+func checkFrontier(t *testing.T, fr *routeFrontier) {
+	t.Helper()
+	if empty_cells := fr.f.CountCells()[core.Empty]; fr.empty_cells != empty_cells {
+		t.Fatalf("frontier counts %v empty cells, the field has %v", fr.empty_cells, empty_cells)
+	}
+
+	// cells away from the routes are only checked once a route comes close to them, so only the cells of the routes are compared
+	can_branch := make([]bool, fr.f.Size())
+	for idx := range can_branch {
+		coords := core.Coordinates{X: idx % int(fr.f.Width), Y: idx / int(fr.f.Width)}
+		if fr.owners[idx] == -1 {
+			continue
+		}
+		choices, err := findChoices(fr.f, fr.checker, coords, fr.finish_blocking)
+		can_branch[idx] = err == nil && len(fr.sections.filter(choices, coords)) > 0
+		if can_branch[idx] != fr.can_branch[idx] {
+			t.Fatalf("frontier says %v can be branched off: %v, the field says %v", coords, fr.can_branch[idx], can_branch[idx])
+		}
+	}
+	for number, route := range fr.routes {
+		bases := 0
+		for _, coords := range routeCells(route, 0) {
+			if idx := cellIndex(fr.f, coords); fr.owners[idx] == number && can_branch[idx] {
+				bases++
+			}
+		}
+		if bases != fr.bases[number] {
+			t.Fatalf("frontier counts %v bases of route #%v, the field has %v", fr.bases[number], number+1, bases)
+		}
+		if alive := fr.alive_positions[number] != -1; alive != (bases > 0) || alive && fr.alive[fr.alive_positions[number]] != number {
+			t.Fatalf("frontier keeps route #%v alive: %v, it has %v bases", number+1, alive, bases)
+		}
+	}
+}
+
+// Frontier updated only around the carved cells stays the same as the one counted over the whole field
+func TestRouteFrontierMatchesRecount(t *testing.T) {
+	for _, only_one_path_near_finish := range []bool{false, true} {
+		for seed := int64(0); seed < 3; seed++ {
+			f := newTestField(t, 15, 15)
+			f.Configuration.Builder.OnlyOnePathNearFinish = only_one_path_near_finish
+			f.MakeEmpty(true)
+			checker, err := configuredChecker(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			attempt := (&generation{checker: checker}).attempt(1)
+			rng := rand.New(rand.NewSource(seed))
+
+			first_route := core.Route{}
+			first_route.Init(f.Start)
+			frontier := newRouteFrontier(f, checker, nil, first_route, false, false)
+			checkFrontier(t, frontier)
+			for route_number := uint(2); route_number < f.Size(); route_number++ {
+				grown, err := growRoute(context.Background(), f, rng, attempt, frontier, route_number)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !grown {
+					break
+				}
+				checkFrontier(t, frontier)
+			}
+			if !frontier.finish_reached {
+				t.Errorf("seed %v: finish was not reached\n%v", seed, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Algorithm that carves a labyrinth in an empty field with start and finish already placed
+// A single call is a single attempt, a failed one is retried on the emptied field
+// All random choices have to come from the provided source, so that the same seed always gives the same labyrinth
+// Generation should stop promptly with the context error once the context is done, steps are reported through the attempt
+type Generator interface {
+	Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error
+}
+
+// Generators available by their names in configuration
+var generators = map[string]Generator{
+	"route-growing":         RouteGrowing{},
+	"recursive-backtracker": RecursiveBacktracker{},
+	"prim":                  Prim{},
+	"kruskal":               Kruskal{},
+	"wilson":                Wilson{},
+	"aldous-broder":         AldousBroder{},
+	"eller":                 Eller{},
+	"sidewinder":            Sidewinder{},
+	"binary-tree":           BinaryTree{},
+	"dungeon":               Dungeon{},
+}
+
+// Find the generator by its name from configuration, route growing is used if the name is empty
+func GeneratorByName(name string) (Generator, error) {
+	if name == "" {
+		return RouteGrowing{}, nil
+	}
+	if generator, ok := generators[name]; ok {
+		return generator, nil
+	}
+
+	return nil, fmt.Errorf("builder error: unknown generation algorithm '%v'", name)
+}
+
+// Grow routes from start until the area is filled according to complexity and the maximum area to cover with walls
+// The only algorithm besides the dungeon that uses these configuration parameters
+type RouteGrowing struct{}
+
+// Grow routes from start and fill everything around them with walls
+func (RouteGrowing) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	routes, err := generateRoutes(ctx, f, rng, attempt)
+	if err != nil {
+		return err
+	}
+	attempt.routes = routes
+	f.FillEmptyCellsWithWalls()
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package builder
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Every algorithm stops with the context error and leaves the field empty once the context is done
+func TestGeneratorsStopWithContext(t *testing.T) {
+	for name := range generators {
+		f := newTestField(t, 21, 21)
+		f.Configuration.Builder.Algorithm = name
+		f.Configuration.Puzzle.KeysAndDoors = 1
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := GenerateLabyrinthContext(ctx, f, WithSeed(1)); err != context.Canceled {
+			t.Errorf("%v: expected the context error, got %v", name, err)
+		}
+		if walls := f.CountCells()[core.Wall]; walls != 0 {
+			t.Errorf("%v: cancelled generation left %v walls on the field", name, walls)
+		}
+	}
+}
+
+// Random source that cancels the context once the chosen amount of numbers was drawn from it
+type cancellingSource struct {
+	rand.Source
+	draws_left int
+	cancel     context.CancelFunc
+}
+
+func (s *cancellingSource) Int63() int64 {
+	if s.draws_left == 0 {
+		s.cancel()
+	}
+	s.draws_left--
+
+	return s.Source.Int63()
+}
+
+// Row generators stop with the context error wherever the context gets cancelled, including after the last row
+func TestRowGeneratorsStopPartway(t *testing.T) {
+	for name, generator := range map[string]Generator{"eller": Eller{}, "sidewinder": Sidewinder{}, "binary-tree": BinaryTree{}} {
+		for draws := 0; ; draws++ {
+			f := newTestField(t, 21, 21)
+			f.MakeEmpty(true)
+			ctx, cancel := context.WithCancel(context.Background())
+			source := &cancellingSource{Source: rand.NewSource(1), draws_left: draws, cancel: cancel}
+			err := generator.Generate(ctx, f, rand.New(source), (&generation{}).attempt(1))
+			cancel()
+			if source.draws_left >= 0 {
+				if err != nil {
+					t.Errorf("%v: %v", name, err)
+				}
+				break
+			}
+			if err != context.Canceled {
+				t.Errorf("%v: cancelled after %v draws, expected the context error, got %v", name, draws, err)
+			}
+		}
+	}
+}
+
+// Count walkable cells, passages between neighboring walkable cells and separate parts of the labyrinth
+func countLabyrinthGraph(f *core.Field) (cells, passages, parts int) {
+	sets := newRoomSets(int(f.Size()))
+	for y := 0; y < int(f.Length); y++ {
+		for x := 0; x < int(f.Width); x++ {
+			coords := core.Coordinates{X: x, Y: y}
+			if !isWalkable(f, coords) {
+				continue
+			}
+			cells++
+			for _, neighbor := range []core.Coordinates{{X: x + 1, Y: y}, {X: x, Y: y + 1}} {
+				if isWalkable(f, neighbor) {
+					passages++
+					sets.union(cellIndex(f, coords), cellIndex(f, neighbor))
+				}
+			}
+		}
+	}
+	for idx := range sets {
+		if coords := (core.Coordinates{X: idx % int(f.Width), Y: idx / int(f.Width)}); isWalkable(f, coords) && sets.find(idx) == idx {
+			parts++
+		}
+	}
+
+	return cells, passages, parts
+}
+
+// Every algorithm connects start with finish, the ones building perfect labyrinths never make loops
+func TestGenerators(t *testing.T) {
+	cases := []struct {
+		algorithm string
+		perfect   bool
+	}{
+		{"route-growing", false},
+		{"recursive-backtracker", true},
+		{"prim", true},
+		{"kruskal", true},
+		{"wilson", true},
+		{"aldous-broder", true},
+		{"eller", true},
+		{"sidewinder", true},
+		{"binary-tree", true},
+		{"dungeon", false},
+	}
+	if len(cases) != len(generators) {
+		t.Fatalf("%v algorithms are tested out of %v", len(cases), len(generators))
+	}
+	// rooms of the grid algorithms are on even coordinates, the other endpoints are between the rooms or past the last ones
+	fields := []struct {
+		size          uint
+		start, finish core.Coordinates
+	}{
+		{21, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 20, Y: 20}},
+		{16, core.Coordinates{X: 0, Y: 4}, core.Coordinates{X: 15, Y: 3}},
+		{21, core.Coordinates{X: 3, Y: 3}, core.Coordinates{X: 17, Y: 13}},
+	}
+	for _, c := range cases {
+		for _, field := range fields {
+			for seed := int64(0); seed < 10; seed++ {
+				f := newTestField(t, field.size, field.size)
+				f.MoveStartAndFinish(field.start, field.finish)
+				f.Configuration.Builder.Algorithm = c.algorithm
+				if _, err := GenerateLabyrinth(f, WithSeed(seed)); err != nil {
+					t.Fatalf("%v, %v -> %v, seed %v: %v", c.algorithm, field.start, field.finish, seed, err)
+				}
+				if !reachesFinish(f) {
+					t.Errorf("%v, %v -> %v, seed %v: finish cannot be reached\n%v", c.algorithm, field.start, field.finish, seed, f)
+				}
+				cells, passages, parts := countLabyrinthGraph(f)
+				if c.perfect && passages != cells-parts {
+					t.Errorf("%v, %v -> %v, seed %v: labyrinth has %v loops\n%v", c.algorithm, field.start, field.finish, seed, passages-(cells-parts), f)
+				}
+				if c.perfect && parts != 1 {
+					t.Errorf("%v, %v -> %v, seed %v: labyrinth is split into %v parts\n%v", c.algorithm, field.start, field.finish, seed, parts, f)
+				}
+			}
+		}
+	}
+}
+
+// Every algorithm keeps masked out cells as walls and reaches the finish placed inside the mask
+func TestGeneratorsRespectMask(t *testing.T) {
+	for name := range generators {
+		for _, shape := range []string{"circle", "triangle"} {
+			f := newTestField(t, 21, 21)
+			f.Configuration.Builder.Algorithm = name
+			f.Configuration.Builder.Placement = "opposite-edges"
+			f.Configuration.Mask.Shape = shape
+			if _, err := GenerateLabyrinth(f, WithSeed(1)); err != nil {
+				t.Fatalf("%v, %v mask: %v", name, shape, err)
+			}
+			m := f.Mask()
+			if m == nil {
+				t.Fatalf("%v, %v mask: mask was not set", name, shape)
+			}
+			for y, row := range m {
+				for x, usable := range row {
+					if cell, _ := f.At(core.Coordinates{X: x, Y: y}); !usable && cell != core.Wall {
+						t.Errorf("%v, %v mask: masked out cell %v is %v\n%v", name, shape, core.Coordinates{X: x, Y: y}, cell, f)
+					}
+				}
+			}
+			if !f.IsUsable(f.Start) || !f.IsUsable(f.Finish) || !reachesFinish(f) {
+				t.Errorf("%v, %v mask: finish cannot be reached from start\n%v", name, shape, f)
+			}
+		}
+	}
+}
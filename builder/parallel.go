@@ -0,0 +1,108 @@
+package builder
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Outcome of a single generation attempt made on its own copy of the field
+type attemptResult struct {
+	field   core.Field
+	attempt *Attempt
+	err     error
+}
+
+// Check that the first labyrinth is better than the second one by the metric from configuration
+// The 'first' metric never prefers a later attempt, so the lowest-numbered successful one is taken
+func isBetterAttempt(f *core.Field, first, second *core.Field) bool {
+	metric := f.Configuration.Builder.ParallelPick
+	if metric == "" || metric == "first" {
+		return false
+	}
+
+	first_difficulty, first_err := measureFinalDifficulty(first)
+	second_difficulty, second_err := measureFinalDifficulty(second)
+	if first_err != nil || second_err != nil {
+		return first_err == nil
+	}
+	switch metric {
+	case "longest-solution":
+		return first_difficulty.SolutionLength > second_difficulty.SolutionLength
+	case "most-dead-ends":
+		return first_difficulty.DeadEnds > second_difficulty.DeadEnds
+	default:
+		return false
+	}
+}
+
+// Run up to the configured amount of attempts at once, each on its own copy of the field with its own random source
+// Seeds of the random sources come from rng, and the lowest-numbered successful attempt is taken unless
+// a metric is configured, so the same seed still gives the same labyrinth. Attempts after the taken one are cancelled
+// Returns the taken attempt, or the last one started and the error of the last failed one if none of them succeeded
+func generateInParallel(ctx context.Context, f *core.Field, rng *rand.Rand, gen *generation, generator Generator, last_attempt uint) (*Attempt, error) {
+	total := f.Configuration.Builder.LabyrinthBuilderAtempts + 1
+	attempt := gen.attempt(last_attempt)
+	var err error
+	for made := uint(0); made < total; {
+		batch := f.Configuration.Builder.ParallelAttempts
+		if batch > total-made {
+			batch = total - made
+		}
+
+		results, cancels := make([]attemptResult, batch), make([]context.CancelFunc, batch)
+		contexts, rngs := make([]context.Context, batch), make([]*rand.Rand, batch)
+		// every attempt is set up before any of them starts, as a finished one cancels the ones after it
+		for i := range results {
+			results[i].attempt = gen.attempt(attempt.Number + uint(i) + 1)
+			contexts[i], cancels[i] = context.WithCancel(ctx)
+			results[i].field = f.Copy()
+			results[i].field.MakeEmpty(true)
+			rngs[i] = rand.New(rand.NewSource(rng.Int63()))
+		}
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				err := runAttempt(contexts[i], &results[i].field, rngs[i], generator, results[i].attempt)
+				mu.Lock()
+				defer mu.Unlock()
+				results[i].err = err
+				if err == nil && (f.Configuration.Builder.ParallelPick == "" || f.Configuration.Builder.ParallelPick == "first") {
+					// later attempts cannot be taken anymore
+					for _, cancel := range cancels[i+1:] {
+						cancel()
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, cancel := range cancels {
+			cancel()
+		}
+		attempt = results[batch-1].attempt
+		made += batch
+
+		if ctx_err := ctx.Err(); ctx_err != nil {
+			return attempt, ctx_err
+		}
+		best := -1
+		for i := range results {
+			if results[i].err == nil && (best == -1 || isBetterAttempt(f, &results[i].field, &results[best].field)) {
+				best = i
+			} else if results[i].err != nil && !isContextError(results[i].err) {
+				err = results[i].err
+			}
+		}
+		if best != -1 {
+			*f = results[best].field
+			return results[best].attempt, nil
+		}
+	}
+
+	return attempt, err
+}
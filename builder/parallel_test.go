@@ -0,0 +1,55 @@
+package builder
+
+import (
+	"context"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Parallel attempts share the parent field and cancel each other, run with -race to check them
+func TestParallelAttemptsAreDeterministic(t *testing.T) {
+	cases := []struct {
+		algorithm     string
+		width, length uint
+		pick          string
+	}{
+		{"binary-tree", 5, 5, "first"},
+		{"recursive-backtracker", 9, 9, "longest-solution"},
+		{"route-growing", 20, 20, "first"},
+		{"route-growing", 20, 20, "most-dead-ends"},
+	}
+	for _, c := range cases {
+		labyrinths := make([]string, 2)
+		for i := range labyrinths {
+			f := newTestField(t, c.width, c.length)
+			f.Configuration.Builder.Algorithm = c.algorithm
+			f.Configuration.Builder.ParallelAttempts = 8
+			f.Configuration.Builder.ParallelPick = c.pick
+			if _, err := GenerateLabyrinth(f, WithSeed(7)); err != nil {
+				t.Fatalf("%v: %v", c.algorithm, err)
+			}
+			if !reachesFinish(f) {
+				t.Fatalf("%v: finish cannot be reached\n%v", c.algorithm, f)
+			}
+			labyrinths[i] = f.String()
+		}
+		if labyrinths[0] != labyrinths[1] {
+			t.Errorf("%v picking %v: the same seed gave different labyrinths\n%v\n%v", c.algorithm, c.pick, labyrinths[0], labyrinths[1])
+		}
+	}
+}
+
+// A cancelled generation stops every parallel attempt and leaves the field empty
+func TestParallelAttemptsStopWithContext(t *testing.T) {
+	f := newTestField(t, 20, 20)
+	f.Configuration.Builder.ParallelAttempts = 4
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GenerateLabyrinthContext(ctx, f, WithSeed(1)); err != context.Canceled {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+	if walls := f.CountCells()[core.Wall]; walls != 0 {
+		t.Errorf("cancelled generation left %v walls on the field", walls)
+	}
+}
@@ -0,0 +1,64 @@
+package builder
+
+import (
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Position of the coordinates in a flat array of all field cells
+func cellIndex(f *core.Field, c core.Coordinates) int {
+	return c.Y*int(f.Width) + c.X
+}
+
+// Check that the cell at the chosen coordinates can be walked through when solving the labyrinth
+func isWalkable(f *core.Field, coords core.Coordinates) bool {
+	cell, err := f.At(coords)
+
+	return err == nil && cell.IsWalkable()
+}
+
+// Find distances from the chosen coordinates to every cell reachable through cells allowed by can_enter
+// Distances are stored in a flat array of all field cells, unreachable cells have -1
+func distancesFrom(f *core.Field, from core.Coordinates, can_enter func(core.Coordinates) bool) []int {
+	distances := make([]int, f.Size())
+	for i := range distances {
+		distances[i] = -1
+	}
+	distances[cellIndex(f, from)] = 0
+	queue := []core.Coordinates{from}
+	for len(queue) > 0 {
+		coords := queue[0]
+		queue = queue[1:]
+		for _, shift := range NeumannShifts {
+			neighbor := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
+			if neighbor.IsValid(f.Width-1, f.Length-1) && distances[cellIndex(f, neighbor)] == -1 && can_enter(neighbor) {
+				distances[cellIndex(f, neighbor)] = distances[cellIndex(f, coords)] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return distances
+}
+
+// Find one of the shortest paths between two cells going through cells allowed by can_enter
+// Returns nil if there is no such path
+func shortestPath(f *core.Field, from, to core.Coordinates, can_enter func(core.Coordinates) bool) []core.Coordinates {
+	distances := distancesFrom(f, to, can_enter)
+	if distances[cellIndex(f, from)] == -1 {
+		return nil
+	}
+
+	path := []core.Coordinates{from}
+	for coords := from; coords != to; {
+		for _, shift := range NeumannShifts {
+			neighbor := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
+			if neighbor.IsValid(f.Width-1, f.Length-1) && distances[cellIndex(f, neighbor)] == distances[cellIndex(f, coords)]-1 {
+				coords = neighbor
+				break
+			}
+		}
+		path = append(path, coords)
+	}
+
+	return path
+}
@@ -0,0 +1,158 @@
+package builder
+
+import (
+	"math"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Find the usable cells of the largest area connected through usable cells, checkpoints are left out
+// Start and finish are placed within it, so that the route between them cannot be cut off by the mask
+func largestUsableArea(f *core.Field) []bool {
+	in_area, checked := make([]bool, f.Size()), make([]bool, f.Size())
+	largest := 0
+	for idx := range checked {
+		coords := core.Coordinates{X: idx % int(f.Width), Y: idx / int(f.Width)}
+		if checked[idx] || !f.IsUsable(coords) {
+			continue
+		}
+
+		area, size := make([]bool, f.Size()), 0
+		for cell_idx, distance := range distancesFrom(f, coords, f.IsUsable) {
+			if distance != -1 {
+				area[cell_idx], checked[cell_idx] = true, true
+				size++
+			}
+		}
+		if size > largest {
+			in_area, largest = area, size
+		}
+	}
+	for _, checkpoint := range f.Checkpoints {
+		in_area[cellIndex(f, checkpoint)] = false
+	}
+
+	return in_area
+}
+
+// Find cells of the area that have a masked out cell or the side of the field next to them
+func borderCells(f *core.Field, in_area []bool) []core.Coordinates {
+	border := make([]core.Coordinates, 0)
+	for idx, ok := range in_area {
+		coords := core.Coordinates{X: idx % int(f.Width), Y: idx / int(f.Width)}
+		if !ok {
+			continue
+		}
+		for _, shift := range NeumannShifts {
+			if !f.IsUsable(core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}) {
+				border = append(border, coords)
+				break
+			}
+		}
+	}
+
+	return border
+}
+
+// Place start and finish on random cells of the border, keeping them at least half of the longer side apart if possible
+func placeOnRandomBorder(f *core.Field, rng *rand.Rand) error {
+	border := borderCells(f, largestUsableArea(f))
+	if len(border) < 2 {
+		return f.Error("Cannot place start and finish, there are less than 2 cells on the border")
+	}
+
+	start := border[rng.Intn(len(border))]
+	far, other := make([]core.Coordinates, 0), make([]core.Coordinates, 0)
+	for _, coords := range border {
+		switch {
+		case coords == start:
+		case coords.Distance(start) >= math.Max(float64(f.Width), float64(f.Length))/2:
+			far = append(far, coords)
+		default:
+			other = append(other, coords)
+		}
+	}
+	if len(far) == 0 {
+		far = other
+	}
+	f.MoveStartAndFinish(start, far[rng.Intn(len(far))])
+
+	return nil
+}
+
+// Place start and finish on the opposite edges along the longer side of the field, the edges follow the mask
+func placeOnOppositeEdges(f *core.Field, rng *rand.Rand) error {
+	in_area := largestUsableArea(f)
+	along_x := f.Width > f.Length || f.Width == f.Length && rng.Intn(2) == 0
+	lines, line_length := int(f.Length), int(f.Width)
+	if !along_x {
+		lines, line_length = int(f.Width), int(f.Length)
+	}
+	at := func(line, position int) core.Coordinates {
+		if along_x {
+			return core.Coordinates{X: position, Y: line}
+		}
+		return core.Coordinates{X: line, Y: position}
+	}
+
+	// the first and the last cell of the area on every line across the chosen direction
+	first_edge, last_edge := make([]core.Coordinates, 0), make([]core.Coordinates, 0)
+	for line := 0; line < lines; line++ {
+		first, last := -1, -1
+		for position := 0; position < line_length; position++ {
+			if in_area[cellIndex(f, at(line, position))] {
+				if first == -1 {
+					first = position
+				}
+				last = position
+			}
+		}
+		if first != last {
+			first_edge, last_edge = append(first_edge, at(line, first)), append(last_edge, at(line, last))
+		}
+	}
+	if len(first_edge) == 0 {
+		return f.Error("Cannot place start and finish, there are no opposite edges")
+	}
+
+	start, finish := first_edge[rng.Intn(len(first_edge))], last_edge[rng.Intn(len(last_edge))]
+	if rng.Intn(2) == 0 {
+		start, finish = finish, start
+	}
+	f.MoveStartAndFinish(start, finish)
+
+	return nil
+}
+
+// Find the walkable cell farthest from the chosen one, the first of them if there are several
+func farthestWalkableCell(f *core.Field, from core.Coordinates) core.Coordinates {
+	farthest, longest := from, 0
+	for idx, distance := range distancesFrom(f, from, func(c core.Coordinates) bool { return isWalkable(f, c) }) {
+		if distance > longest {
+			farthest, longest = core.Coordinates{X: idx % int(f.Width), Y: idx / int(f.Width)}, distance
+		}
+	}
+
+	return farthest
+}
+
+// Move start and finish of the carved labyrinth to the ends of its longest route
+// The longest route is always found in labyrinths without loops, braided ones get one of the long routes
+func placeFarthestPair(f *core.Field) {
+	start := farthestWalkableCell(f, f.Start)
+	f.MoveStartAndFinish(start, farthestWalkableCell(f, start))
+}
+
+// Place start and finish according to the placement mode from configuration
+// The farthest pair starts from random cells on the border, they are moved once the labyrinth is carved
+func placeStartAndFinish(f *core.Field, rng *rand.Rand) error {
+	switch f.Configuration.Builder.Placement {
+	case "random-border", "farthest-pair":
+		return placeOnRandomBorder(f, rng)
+	case "opposite-edges":
+		return placeOnOppositeEdges(f, rng)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Every placement mode puts start and finish on usable cells connected by the labyrinth
+func TestPlacementModes(t *testing.T) {
+	for _, placement := range []string{"random-border", "opposite-edges", "farthest-pair"} {
+		for seed := int64(0); seed < 3; seed++ {
+			f := newTestField(t, 21, 15)
+			f.Configuration.Builder.Placement = placement
+			if _, err := GenerateLabyrinth(f, WithSeed(seed)); err != nil {
+				t.Fatalf("%v, seed %v: %v", placement, seed, err)
+			}
+			if f.Start == f.Finish || !reachesFinish(f) {
+				t.Errorf("%v, seed %v: finish cannot be reached\n%v", placement, seed, f)
+			}
+			if start, _ := f.At(f.Start); start != core.Start {
+				t.Errorf("%v, seed %v: start cell is %v", placement, seed, start)
+			}
+		}
+	}
+}
+
+// The farthest pair is placed once after the difficulty rounds, all of which start from the same cell
+func TestFarthestPairIsPlacedAfterDifficulty(t *testing.T) {
+	f := newTestField(t, 21, 21)
+	f.Configuration.Builder.Algorithm = "recursive-backtracker"
+	f.Configuration.Builder.Placement = "farthest-pair"
+	// the target cannot be reached, so that every round is made
+	f.Configuration.Difficulty.MinDeadEnds = 10000
+	f.Configuration.Difficulty.Attempts = 3
+	var recorder Recorder
+	_, err := GenerateLabyrinth(f, WithSeed(2), WithHook(recorder.Hook()))
+	var difficulty_err DifficultyError
+	if !errors.As(err, &difficulty_err) {
+		t.Fatalf("expected the difficulty error, got %v", err)
+	}
+
+	restarts := make([]core.Coordinates, 0)
+	for _, p := range recorder.Events() {
+		if p.Kind == AttemptRestarted {
+			restarts = append(restarts, p.Coords)
+		}
+	}
+	if len(restarts) != 2 {
+		t.Fatalf("expected 2 restarts, got %v", len(restarts))
+	}
+	if restarts[0] != restarts[1] {
+		t.Errorf("rounds started from %v and %v", restarts[0], restarts[1])
+	}
+
+	// labyrinths without loops have a single longest route, which ends at the placed start and finish
+	distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return isWalkable(f, c) })
+	if farthest := farthestWalkableCell(f, f.Start); distances[cellIndex(f, farthest)] != distances[cellIndex(f, f.Finish)] {
+		t.Errorf("finish %v is closer to start than %v", f.Finish, farthest)
+	}
+	if measured, _ := MeasureDifficulty(f); measured != difficulty_err.Closest {
+		t.Errorf("the error describes %+v, the field has %+v", difficulty_err.Closest, measured)
+	}
+}
+
+// Generation that is rejected leaves start and finish where they were, even if the placement would move them
+func TestRejectedGenerationKeepsField(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(f *core.Field)
+	}{
+		{"unknown algorithm", func(f *core.Field) { f.Configuration.Builder.Algorithm = "unknown" }},
+		{"unknown checker", func(f *core.Field) { f.Configuration.Builder.Checker = "unknown" }},
+		{"checkpoint masked out", func(f *core.Field) {
+			if err := f.SetCheckpoints(core.Coordinates{X: 20, Y: 0}); err != nil {
+				t.Fatal(err)
+			}
+			f.Configuration.Mask.Shape = "circle"
+		}},
+	}
+	for _, c := range cases {
+		f := newTestField(t, 21, 21)
+		f.Configuration.Builder.Placement = "random-border"
+		c.change(f)
+		before := f.String()
+		if _, err := GenerateLabyrinth(f, WithSeed(1)); err == nil {
+			t.Fatalf("%v: expected an error", c.name)
+		}
+		if after := f.String(); after != before || f.Start != (core.Coordinates{X: 0, Y: 0}) || f.Finish != (core.Coordinates{X: 20, Y: 20}) {
+			t.Errorf("%v: field changed from\n%v\nto\n%v", c.name, before, after)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package builder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Kind of step made by the labyrinth builder
+type ProgressKind uint
+
+// Enum for possible steps made by the labyrinth builder
+const (
+	CellCarved       ProgressKind = iota // cell became a part of the route being built
+	RouteStarted                         // new route was branched off one of the existing routes
+	AttemptRestarted                     // previous attempt failed and the field was emptied for the next one
+	AttemptTaken                         // labyrinth of the attempt was left on the field, reported once at the end of the generation
+)
+
+// String representation of the progress kind
+func (k ProgressKind) String() string {
+	switch k {
+	case CellCarved:
+		return "cell carved"
+	case RouteStarted:
+		return "route started"
+	case AttemptRestarted:
+		return "attempt restarted"
+	case AttemptTaken:
+		return "attempt taken"
+	default:
+		return "unknown"
+	}
+}
+
+// Single step made by the labyrinth builder
+type Progress struct {
+	Kind           ProgressKind
+	Coords         core.Coordinates // carved cell or the cell the new route branches off
+	Attempt        uint             // number of the generation attempt, starting with 1
+	Route          uint             // number of the route being built, starting with 1
+	Choices        uint             // amount of cells the carved one was chosen from
+	AwayFromFinish bool             // choice was made against the distance to finish because of the complexity
+	Reason         string           `json:",omitempty"` // why the previous attempt failed
+}
+
+// Callback which receives every step made by the labyrinth builder
+type Hook func(Progress)
+
+// Report every step made by the labyrinth builder to the hook
+// Parallel attempts call the hook from several goroutines at once, their steps are told apart by the attempt number
+func WithHook(hook Hook) Option {
+	return func(s *settings) {
+		s.hook = hook
+	}
+}
+
+// Collector of the steps made by the labyrinth builder, safe to use from several goroutines
+type Recorder struct {
+	mu     sync.Mutex
+	events []Progress
+}
+
+// Create a hook that records every step
+func (r *Recorder) Hook() Hook {
+	return func(p Progress) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, p)
+	}
+}
+
+// Get a copy of all recorded steps
+func (r *Recorder) Events() []Progress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Progress(nil), r.events...)
+}
+
+// Save serialized steps to file in existing directory
+func (r *Recorder) SaveToFile(file_path string) error {
+	serialized_data, err := json.Marshal(r.Events())
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file_path, serialized_data, 0644)
+}
+
+// Deserialize steps saved by the recorder
+func LoadRecording(file_path string) ([]Progress, error) {
+	serialized_data, err := ioutil.ReadFile(file_path)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Progress
+	if err := json.Unmarshal(serialized_data, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Number of the attempt the recorded generation took, or the last reported one if none was taken
+func takenAttempt(events []Progress) uint {
+	last := uint(0)
+	for _, p := range events {
+		if p.Kind == AttemptTaken {
+			return p.Attempt
+		}
+		if p.Attempt > last {
+			last = p.Attempt
+		}
+	}
+
+	return last
+}
+
+// Repeat the recorded steps of the taken attempt on the field, which should have the same size, start and finish as the recorded one
+// Steps of other attempts are left out, as parallel attempts report them mixed together. If no attempt was taken,
+// e.g. the generation failed, the last reported attempt is repeated instead
+// The hook, which can be nil, receives each step right after it was applied, e.g. to draw the field
+// Carved cells are set to Path the way the builder sees them before the rest is filled with walls
+// Only the carving is repeated, braiding, terrain, keys and doors applied after it are not recorded
+func Replay(f *core.Field, events []Progress, hook Hook) error {
+	taken := takenAttempt(events)
+	f.MakeEmpty(true)
+	for _, p := range events {
+		if p.Attempt != taken {
+			continue
+		}
+		if p.Kind == CellCarved {
+			if err := f.Set(core.Path, p.Coords); err != nil {
+				return err
+			}
+		}
+		if hook != nil {
+			hook(p)
+		}
+	}
+
+	return nil
+}
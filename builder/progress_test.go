@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Every reported step carries the number of the attempt it was made in, and restarts separate the attempts
+func TestHookReportsAttemptNumbers(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		f := newTestField(t, 20, 20)
+		f.Configuration.Builder.ParallelAttempts = 1
+		var recorder Recorder
+		report, err := GenerateLabyrinth(f, WithSeed(seed), WithHook(recorder.Hook()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attempt := uint(1)
+		for _, p := range recorder.Events() {
+			if p.Kind == AttemptRestarted {
+				attempt++
+			}
+			if p.Attempt != attempt {
+				t.Fatalf("seed %v: %v was reported in attempt %v, expected %v", seed, p.Kind, p.Attempt, attempt)
+			}
+		}
+		if attempt != report.Attempts {
+			t.Errorf("seed %v: steps were reported in %v attempts, the report has %v", seed, attempt, report.Attempts)
+		}
+	}
+}
+
+// Replay repeats only the taken attempt, even if parallel attempts reported their steps mixed together
+func TestReplayTakesOneAttempt(t *testing.T) {
+	mixed := false
+	for seed := int64(0); seed < 5; seed++ {
+		f := newTestField(t, 32, 32)
+		f.Configuration.Builder.ParallelAttempts = 4
+		var recorder Recorder
+		if _, err := GenerateLabyrinth(f, WithSeed(seed), WithHook(recorder.Hook())); err != nil {
+			t.Fatal(err)
+		}
+		events := recorder.Events()
+		taken := events[len(events)-1]
+		if taken.Kind != AttemptTaken {
+			t.Fatalf("seed %v: the last step is %v", seed, taken.Kind)
+		}
+
+		replayed := newTestField(t, 32, 32)
+		err := Replay(replayed, events, func(p Progress) {
+			if p.Attempt != taken.Attempt {
+				t.Fatalf("seed %v: step of attempt %v was replayed, attempt %v was taken", seed, p.Attempt, taken.Attempt)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range events {
+			mixed = mixed || p.Attempt != taken.Attempt && p.Kind == CellCarved
+		}
+		for idx := 0; idx < int(f.Size()); idx++ {
+			coords := core.Coordinates{X: idx % int(f.Width), Y: idx / int(f.Width)}
+			if cell, _ := replayed.At(coords); cell == core.Path && !isWalkable(f, coords) {
+				t.Fatalf("seed %v: replay carved %v, which is not walkable in the generated labyrinth\n%v", seed, coords, replayed)
+			}
+		}
+	}
+	if !mixed {
+		t.Error("none of the generations reported steps of several attempts")
+	}
+}
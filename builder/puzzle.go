@@ -0,0 +1,91 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Check that the cell can be walked through by someone who has keys of all colours below 'keys'
+func canEnterWithKeys(f *core.Field, coords core.Coordinates, keys uint) bool {
+	cell, err := f.At(coords)
+
+	return err == nil && (cell.IsWalkable() || cell.IsDoor() && cell.Colour() < keys)
+}
+
+// Place pairs of coloured keys and doors according to the configuration
+// Every door is placed on the solution so that it cannot be bypassed, and its key can be reached without opening it
+// Placement stops with the context error once the context is done
+func placeKeysAndDoors(ctx context.Context, f *core.Field, rng *rand.Rand) error {
+	pairs := int(f.Configuration.Puzzle.KeysAndDoors)
+	if pairs == 0 {
+		return nil
+	}
+
+	solution := shortestPath(f, f.Start, f.Finish, func(c core.Coordinates) bool { return isWalkable(f, c) })
+	if len(solution) < pairs+2 {
+		return f.Error(fmt.Sprintf("Cannot place %v doors, solution is too short or missing", pairs))
+	}
+	on_solution := make([]bool, f.Size())
+	for _, coords := range solution {
+		on_solution[cellIndex(f, coords)] = true
+	}
+
+	// every door gets its own part of the solution, so they go in the order of their colours
+	interior := solution[1 : len(solution)-1]
+	for colour := 0; colour < pairs; colour++ {
+		part := append([]core.Coordinates{}, interior[colour*len(interior)/pairs:(colour+1)*len(interior)/pairs]...)
+		rng.Shuffle(len(part), func(i, j int) { part[i], part[j] = part[j], part[i] })
+		placed := false
+		for _, coords := range part {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			old_cell, _ := f.At(coords)
+			f.Set(core.DoorCell(uint(colour)), coords)
+			distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return canEnterWithKeys(f, c, uint(colour)) })
+			if distances[cellIndex(f, f.Finish)] == -1 {
+				placed = true
+				break
+			}
+			// there is a way around the door, it would not lock anything
+			f.Set(old_cell, coords)
+		}
+		if !placed {
+			return f.Error(fmt.Sprintf("Cannot place door #%v so that it cannot be bypassed", colour+1))
+		}
+	}
+
+	for colour := 0; colour < pairs; colour++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return canEnterWithKeys(f, c, uint(colour)) })
+		off_solution, on_solution_candidates := make([]core.Coordinates, 0), make([]core.Coordinates, 0)
+		for idx, distance := range distances {
+			coords := core.Coordinates{X: idx % int(f.Width), Y: idx / int(f.Width)}
+			if cell, _ := f.At(coords); distance <= 0 || cell != core.Empty && !cell.IsTerrain() {
+				continue
+			}
+			if on_solution[idx] {
+				on_solution_candidates = append(on_solution_candidates, coords)
+			} else {
+				off_solution = append(off_solution, coords)
+			}
+		}
+
+		// keys away from the solution make the player take a detour
+		candidates := off_solution
+		if len(candidates) == 0 {
+			candidates = on_solution_candidates
+		}
+		if len(candidates) == 0 {
+			return f.Error(fmt.Sprintf("Cannot place key #%v, there are no free cells before its door", colour+1))
+		}
+		f.Set(core.KeyCell(uint(colour)), candidates[rng.Intn(len(candidates))])
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Find every cell the function matches
+func findCells(f *core.Field, matches func(core.Coordinates) bool) []core.Coordinates {
+	found := make([]core.Coordinates, 0)
+	for y := 0; y < int(f.Length); y++ {
+		for x := 0; x < int(f.Width); x++ {
+			if matches(core.Coordinates{X: x, Y: y}) {
+				found = append(found, core.Coordinates{X: x, Y: y})
+			}
+		}
+	}
+
+	return found
+}
+
+// Every door locks the finish and its key can be picked up with the keys of the doors before it
+func TestKeysAndDoors(t *testing.T) {
+	for pairs := uint(1); pairs <= core.KeyColours; pairs++ {
+		for seed := int64(0); seed < 5; seed++ {
+			f := newTestField(t, 21, 21)
+			f.Configuration.Puzzle.KeysAndDoors = pairs
+			if _, err := GenerateLabyrinth(f, WithSeed(seed)); err != nil {
+				t.Fatalf("%v pairs, seed %v: %v", pairs, seed, err)
+			}
+
+			for colour := uint(0); colour < pairs; colour++ {
+				cells := f.CountCells()
+				if cells[core.DoorCell(colour)] != 1 || cells[core.KeyCell(colour)] != 1 {
+					t.Fatalf("%v pairs, seed %v: expected one key and one door of colour #%v\n%v", pairs, seed, colour+1, f)
+				}
+				key := findCells(f, func(c core.Coordinates) bool {
+					cell, _ := f.At(c)
+					return cell == core.KeyCell(colour)
+				})[0]
+				distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return canEnterWithKeys(f, c, colour) })
+				if distances[cellIndex(f, key)] == -1 {
+					t.Errorf("%v pairs, seed %v: key #%v cannot be reached\n%v", pairs, seed, colour+1, f)
+				}
+				if distances[cellIndex(f, f.Finish)] != -1 {
+					t.Errorf("%v pairs, seed %v: finish can be reached without key #%v\n%v", pairs, seed, colour+1, f)
+				}
+			}
+			distances := distancesFrom(f, f.Start, func(c core.Coordinates) bool { return canEnterWithKeys(f, c, pairs) })
+			if distances[cellIndex(f, f.Finish)] == -1 {
+				t.Errorf("%v pairs, seed %v: finish cannot be reached with all keys\n%v", pairs, seed, f)
+			}
+			if _, err := MeasureDifficulty(f); err != nil {
+				t.Errorf("%v pairs, seed %v: %v", pairs, seed, err)
+			}
+		}
+	}
+}
+
+// Doors that cannot be placed only cost the attempt they failed in
+func TestKeysAndDoorsFailureIsRetried(t *testing.T) {
+	retried := false
+	for seed := int64(0); seed < 20; seed++ {
+		f := newTestField(t, 7, 7)
+		f.Configuration.Builder.ParallelAttempts = 1
+		f.Configuration.Builder.MaxAreaToCoverWithWalls = 80
+		f.Configuration.Puzzle.KeysAndDoors = core.KeyColours
+		var recorder Recorder
+		if _, err := GenerateLabyrinth(f, WithSeed(seed), WithHook(recorder.Hook())); err != nil {
+			continue
+		}
+		for _, p := range recorder.Events() {
+			if p.Kind == AttemptRestarted && (strings.Contains(p.Reason, "door") || strings.Contains(p.Reason, "key")) {
+				retried = true
+			}
+		}
+	}
+	if !retried {
+		t.Error("none of the generations recovered from a failed placement of keys and doors")
+	}
+}
+
+// Keys and doors rely on start and finish staying where the doors were placed for them
+func TestKeysAndDoorsRejectFarthestPair(t *testing.T) {
+	f := newTestField(t, 15, 15)
+	f.Configuration.Builder.Placement = "farthest-pair"
+	f.Configuration.Puzzle.KeysAndDoors = 1
+	if _, err := GenerateLabyrinth(f, WithSeed(1)); err == nil {
+		t.Error("expected an error")
+	}
+}
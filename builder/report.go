@@ -0,0 +1,37 @@
+package builder
+
+import (
+	"time"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Summary of the labyrinth generation, the routes part is only filled by route-growing and dungeon algorithms
+type GenerationReport struct {
+	Attempts       uint            // generation attempts made, including the failed ones and the ones made for difficulty ranges
+	Routes         uint            // routes built in the taken attempt
+	Cells          core.CellCounts // amount of cells of every type in the generated labyrinth
+	EmptyArea      float64         // percentage of the reachable area left empty when routes stopped growing
+	SafetyLimitHit bool            // routes stopped growing because there were as many of them as cells on the field
+	Duration       time.Duration
+}
+
+// Statistics of the routes grown in a single generation attempt
+type routesSummary struct {
+	routes           uint
+	empty_area       float64
+	safety_limit_hit bool
+}
+
+// Destination of the messages about the generation, *log.Logger fits it
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// Write messages about the generation to the logger, the builder stays silent without it
+// Parallel attempts write to the logger from several goroutines at once
+func WithLogger(logger Logger) Option {
+	return func(s *settings) {
+		s.logger = logger
+	}
+}
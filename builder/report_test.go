@@ -0,0 +1,43 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Logger that keeps every message, parallel attempts write to it from several goroutines
+type collectingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// Keep the formatted message
+func (l *collectingLogger) Printf(format string, v ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+// Routes of the taken attempt are summarized in the report, messages go only to the logger
+func TestReportSummarizesTakenAttempt(t *testing.T) {
+	for _, parallel_attempts := range []uint{1, 4} {
+		f := newTestField(t, 20, 20)
+		f.Configuration.Builder.ParallelAttempts = parallel_attempts
+		var logger collectingLogger
+		report, err := GenerateLabyrinth(f, WithSeed(3), WithLogger(&logger))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Attempts == 0 || report.Routes == 0 {
+			t.Errorf("parallel attempts=%v: report has no attempts or routes: %+v", parallel_attempts, report)
+		}
+		if report.EmptyArea > f.Configuration.Builder.MaxAreaToCoverWithWalls {
+			t.Errorf("parallel attempts=%v: empty area %v is above the configured %v", parallel_attempts, report.EmptyArea, f.Configuration.Builder.MaxAreaToCoverWithWalls)
+		}
+		if len(logger.messages) == 0 || !strings.HasPrefix(logger.messages[len(logger.messages)-1], "Area filled!") {
+			t.Errorf("parallel attempts=%v: unexpected messages %q", parallel_attempts, logger.messages)
+		}
+	}
+}
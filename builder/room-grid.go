@@ -0,0 +1,391 @@
+package builder
+
+import (
+	"math/rand"
+	"sort"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Labyrinth made of rooms on cells with even coordinates and passages between them
+// Rooms are numbered row by row, the grid algorithms only decide which of them to connect
+type roomGrid struct {
+	f             *core.Field
+	width, length int    // amount of rooms along each side of the field
+	usable        []bool // rooms that can be reached from the room of the start cell without crossing the mask
+}
+
+// Fill the field with walls and create a grid of rooms on top of it
+func newRoomGrid(f *core.Field) roomGrid {
+	f.MakeEmpty(true)
+	f.FillEmptyCellsWithWalls()
+
+	g := roomGrid{f: f, width: (int(f.Width) + 1) / 2, length: (int(f.Length) + 1) / 2}
+	g.usable = make([]bool, g.size())
+	first := g.roomAt(f.Start)
+	if !f.IsUsable(g.coordinates(first)) {
+		return g
+	}
+	g.usable[first] = true
+	queue := []int{first}
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range g.allNeighbors(room) {
+			if !g.usable[neighbor] && f.IsUsable(g.coordinates(neighbor)) && f.IsUsable(g.passage(room, neighbor)) {
+				g.usable[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return g
+}
+
+// Amount of rooms in the grid
+func (g roomGrid) size() int {
+	return g.width * g.length
+}
+
+// Number of the room in the chosen column and row of rooms
+func (g roomGrid) room(x, y int) int {
+	return y*g.width + x
+}
+
+// Column and row of the room
+func (g roomGrid) position(room int) (int, int) {
+	return room % g.width, room / g.width
+}
+
+// Coordinates of the cell with the room
+func (g roomGrid) coordinates(room int) core.Coordinates {
+	x, y := g.position(room)
+
+	return core.Coordinates{X: x * 2, Y: y * 2}
+}
+
+// Room which has the cell or is the closest to it, rooms that can be reached from the cell without crossing the mask go first
+func (g roomGrid) roomAt(c core.Coordinates) int {
+	xs, ys := []int{c.X}, []int{c.Y}
+	if c.X%2 == 1 {
+		xs = []int{c.X - 1, c.X + 1}
+	}
+	if c.Y%2 == 1 {
+		ys = []int{c.Y - 1, c.Y + 1}
+	}
+	for _, y := range ys {
+		for _, x := range xs {
+			if g.f.IsUsable(core.Coordinates{X: x, Y: y}) && (g.f.IsUsable(core.Coordinates{X: x, Y: c.Y}) || g.f.IsUsable(core.Coordinates{X: c.X, Y: y})) {
+				return g.room(x/2, y/2)
+			}
+		}
+	}
+
+	return g.room(c.X/2, c.Y/2)
+}
+
+// Amount of rooms that can be a part of the labyrinth
+func (g roomGrid) usableSize() int {
+	counter := 0
+	for _, usable := range g.usable {
+		if usable {
+			counter++
+		}
+	}
+
+	return counter
+}
+
+// Coordinates of the cell between two neighboring rooms
+func (g roomGrid) passage(a, b int) core.Coordinates {
+	from, to := g.coordinates(a), g.coordinates(b)
+
+	return core.Coordinates{X: (from.X + to.X) / 2, Y: (from.Y + to.Y) / 2}
+}
+
+// Usable rooms next to the chosen one in Von Neumann's neighborhood that can be connected to it
+func (g roomGrid) neighbors(room int) []int {
+	neighbors := make([]int, 0, len(NeumannShifts))
+	for _, neighbor := range g.allNeighbors(room) {
+		if g.usable[neighbor] && g.f.IsUsable(g.passage(room, neighbor)) {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+
+	return neighbors
+}
+
+// Rooms next to the chosen one in Von Neumann's neighborhood, including the masked out ones
+func (g roomGrid) allNeighbors(room int) []int {
+	x, y := g.position(room)
+	neighbors := make([]int, 0, len(NeumannShifts))
+	for _, shift := range NeumannShifts {
+		if nx, ny := x+shift[0], y+shift[1]; nx >= 0 && nx < g.width && ny >= 0 && ny < g.length {
+			neighbors = append(neighbors, g.room(nx, ny))
+		}
+	}
+
+	return neighbors
+}
+
+// Open the room unless it is not usable
+func (g roomGrid) carve(room int) {
+	if g.usable[room] {
+		g.f.Set(core.Empty, g.coordinates(room))
+	}
+}
+
+// Open both rooms and the passage between them, rooms have to be neighbors
+// Nothing is opened if the rooms cannot be connected because of the mask
+func (g roomGrid) connect(a, b int) {
+	if !g.usable[a] || !g.usable[b] || !g.f.IsUsable(g.passage(a, b)) {
+		return
+	}
+	g.carve(a)
+	g.carve(b)
+	g.f.Set(core.Empty, g.passage(a, b))
+}
+
+// Connect usable rooms that were left unreachable from the start, e.g. when the mask cut a row algorithm short
+// Every unreachable part gets exactly one passage, so a perfect labyrinth stays perfect
+func (g roomGrid) connectUnreachable(rng *rand.Rand) {
+	reached := make([]bool, g.size())
+	first := g.roomAt(g.f.Start)
+	if !g.usable[first] {
+		return
+	}
+	reached[first] = true
+	queue := []int{first}
+	for {
+		for len(queue) > 0 {
+			room := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range g.neighbors(room) {
+				if !reached[neighbor] && isWalkable(g.f, g.passage(room, neighbor)) {
+					reached[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		// pick one of the passages between reached and unreachable rooms
+		passages := make([][2]int, 0)
+		for room, is_reached := range reached {
+			if !is_reached {
+				continue
+			}
+			for _, neighbor := range g.neighbors(room) {
+				if !reached[neighbor] {
+					passages = append(passages, [2]int{room, neighbor})
+				}
+			}
+		}
+		if len(passages) == 0 {
+			return
+		}
+		passage := passages[rng.Intn(len(passages))]
+		g.connect(passage[0], passage[1])
+		reached[passage[1]] = true
+		queue = append(queue, passage[1])
+	}
+}
+
+// Walkable cells next to the chosen one in Von Neumann's neighborhood, leaving out the excluded cell
+func (g roomGrid) walkableAround(c, excluded core.Coordinates) []core.Coordinates {
+	walkable := make([]core.Coordinates, 0, len(NeumannShifts))
+	for _, shift := range NeumannShifts {
+		neighbor := core.Coordinates{X: c.X + shift[0], Y: c.Y + shift[1]}
+		if neighbor != excluded && isWalkable(g.f, neighbor) {
+			walkable = append(walkable, neighbor)
+		}
+	}
+
+	return walkable
+}
+
+// Count walkable cells that can be reached from the chosen one
+func (g roomGrid) reachableFrom(c core.Coordinates) int {
+	counter := 0
+	for _, distance := range distancesFrom(g.f, c, func(c core.Coordinates) bool { return isWalkable(g.f, c) }) {
+		if distance != -1 {
+			counter++
+		}
+	}
+
+	return counter
+}
+
+// Rooms on both sides of the passage cell, passages have exactly one odd coordinate
+func passageRooms(c core.Coordinates) (core.Coordinates, core.Coordinates, bool) {
+	switch {
+	case c.X%2 == 1 && c.Y%2 == 0:
+		return core.Coordinates{X: c.X - 1, Y: c.Y}, core.Coordinates{X: c.X + 1, Y: c.Y}, true
+	case c.X%2 == 0 && c.Y%2 == 1:
+		return core.Coordinates{X: c.X, Y: c.Y - 1}, core.Coordinates{X: c.X, Y: c.Y + 1}, true
+	default:
+		return core.Coordinates{}, core.Coordinates{}, false
+	}
+}
+
+// Open a passage between a room reached from the chosen cell and a walkable room that is not, the passage cannot touch the chosen cell
+// Returns the opened passage, false if there is no such passage
+func (g roomGrid) reconnect(from core.Coordinates) (core.Coordinates, bool) {
+	distances := distancesFrom(g.f, from, func(c core.Coordinates) bool { return isWalkable(g.f, c) })
+	reached := func(c core.Coordinates) bool {
+		return c.IsValid(g.f.Width-1, g.f.Length-1) && distances[cellIndex(g.f, c)] != -1
+	}
+	for idx := range distances {
+		passage := core.Coordinates{X: idx % int(g.f.Width), Y: idx / int(g.f.Width)}
+		a, b, ok := passageRooms(passage)
+		if cell, _ := g.f.At(passage); !ok || cell != core.Wall || !g.f.IsUsable(passage) ||
+			passage.Distance(from) <= 1 || !isWalkable(g.f, a) || !isWalkable(g.f, b) {
+			continue
+		}
+		if reached(a) != reached(b) {
+			g.f.Set(core.Empty, passage)
+			return passage, true
+		}
+	}
+
+	return core.Coordinates{}, false
+}
+
+// Wall up one of the passages of the loop without cutting anything off from the chosen cell, returns false if none of them can be walled up
+// Passages between two rooms go first, a passage with more walkable neighbors can only be walled up if the part it cuts off gets another passage
+func (g roomGrid) breakLoop(from core.Coordinates, loop []core.Coordinates) bool {
+	reachable := g.reachableFrom(from)
+	passages := make([]core.Coordinates, 0, len(loop))
+	for _, coords := range loop {
+		if _, _, ok := passageRooms(coords); ok {
+			if cell, _ := g.f.At(coords); cell == core.Empty {
+				passages = append(passages, coords)
+			}
+		}
+	}
+	sort.SliceStable(passages, func(i, j int) bool {
+		return len(g.walkableAround(passages[i], passages[i])) < len(g.walkableAround(passages[j], passages[j]))
+	})
+	for _, passage := range passages {
+		g.f.Set(core.Wall, passage)
+		if g.reachableFrom(from) == reachable-1 {
+			return true
+		}
+		// the opened passage takes the place of the walled up one
+		if opened, ok := g.reconnect(from); ok {
+			if g.reachableFrom(from) == reachable {
+				return true
+			}
+			g.f.Set(core.Wall, opened)
+		}
+		g.f.Set(core.Empty, passage)
+	}
+
+	return false
+}
+
+// Wall up passages until walkable neighbors of the cell are only connected through it
+func (g roomGrid) breakLoopsThrough(c core.Coordinates) {
+	for {
+		walkable := g.walkableAround(c, c)
+		var loop []core.Coordinates
+		for i := range walkable {
+			for j := i + 1; j < len(walkable) && loop == nil; j++ {
+				loop = shortestPath(g.f, walkable[i], walkable[j], func(coords core.Coordinates) bool { return coords != c && isWalkable(g.f, coords) })
+			}
+		}
+		if loop == nil || !g.breakLoop(c, loop) {
+			return
+		}
+	}
+}
+
+// Connect the cell to the labyrinth without making loops, so a perfect labyrinth stays perfect
+// A cell between rooms can touch passages that are already connected in some other way, those loops are broken by walling up one of the passages
+func (g roomGrid) attach(c core.Coordinates) {
+	if len(g.walkableAround(c, c)) > 0 {
+		g.breakLoopsThrough(c)
+		return
+	}
+
+	usable := make([]core.Coordinates, 0, len(NeumannShifts))
+	for _, shift := range NeumannShifts {
+		if neighbor := (core.Coordinates{X: c.X + shift[0], Y: c.Y + shift[1]}); g.f.IsUsable(neighbor) {
+			usable = append(usable, neighbor)
+		}
+	}
+	// a cell touching a single walkable cell besides the chosen one makes a dead end
+	for _, neighbor := range usable {
+		if len(g.walkableAround(neighbor, c)) == 1 {
+			g.f.Set(core.Empty, neighbor)
+			return
+		}
+	}
+	// cells between four rooms only touch passages, the rooms of which are already connected in some other way
+	for _, neighbor := range usable {
+		if rooms := g.walkableAround(neighbor, c); len(rooms) == 2 {
+			g.f.Set(core.Empty, neighbor)
+			loop := shortestPath(g.f, rooms[0], rooms[1], func(coords core.Coordinates) bool { return coords != neighbor && isWalkable(g.f, coords) })
+			if loop != nil {
+				g.breakLoop(c, loop)
+			}
+			return
+		}
+	}
+
+	// nothing walkable is close, the way to the room goes along the X axis first unless the mask is in the way
+	room := g.coordinates(g.roomAt(c))
+	corner := core.Coordinates{X: room.X, Y: c.Y}
+	if !g.f.IsUsable(corner) {
+		corner = core.Coordinates{X: c.X, Y: room.Y}
+	}
+	g.f.Set(core.Empty, corner)
+	g.f.Set(core.Empty, room)
+}
+
+// Connect start and finish to the rooms around them, should be called after all rooms are carved
+// Returns an error if the mask does not let the finish be reached from the start
+func (g roomGrid) attachStartAndFinish() error {
+	g.attach(g.f.Start)
+	g.attach(g.f.Finish)
+	// passages opened around the finish can make loops through the start
+	g.breakLoopsThrough(g.f.Start)
+	if shortestPath(g.f, g.f.Start, g.f.Finish, func(c core.Coordinates) bool { return isWalkable(g.f, c) }) == nil {
+		return g.f.Error("Finish cannot be reached from start inside the mask")
+	}
+
+	return nil
+}
+
+// Disjoint sets of rooms, used to keep track of which rooms are already connected
+type roomSets []int
+
+// Create sets with one room in each
+func newRoomSets(size int) roomSets {
+	sets := make(roomSets, size)
+	for i := range sets {
+		sets[i] = i
+	}
+
+	return sets
+}
+
+// Find the representative room of the set with the chosen room
+func (s roomSets) find(room int) int {
+	for s[room] != room {
+		s[room] = s[s[room]]
+		room = s[room]
+	}
+
+	return room
+}
+
+// Merge sets of both rooms, returns false if they were already in the same set
+func (s roomSets) union(a, b int) bool {
+	a, b = s.find(a), s.find(b)
+	if a == b {
+		return false
+	}
+	s[b] = a
+
+	return true
+}
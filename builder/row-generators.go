@@ -0,0 +1,144 @@
+package builder
+
+import (
+	"context"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Eller's algorithm, goes row by row keeping track of which rooms of the current row are already connected
+type Eller struct{}
+
+// Sidewinder algorithm, carves runs of rooms along rows and connects every run to the row above
+// The top row is always one straight corridor
+type Sidewinder struct{}
+
+// Binary tree algorithm, connects every room to the one above or to the right of it
+// The top row and the right column are always straight corridors
+type BinaryTree struct{}
+
+// Carve rooms row by row, joining rooms of different sets in a row and continuing every set into the next row
+func (Eller) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	row_sets, next_set := make([]int, g.width), 1
+
+	for y := 0; y < g.length; y++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for x := range row_sets {
+			if row_sets[x] == 0 {
+				row_sets[x], next_set = next_set, next_set+1
+			}
+			g.carve(g.room(x, y))
+		}
+
+		// the last row has to join all sets that are left, otherwise some rooms would be unreachable
+		last_row := y == g.length-1
+		for x := 0; x < g.width-1; x++ {
+			if row_sets[x] == row_sets[x+1] || !last_row && rng.Intn(2) == 0 {
+				continue
+			}
+			g.connect(g.room(x, y), g.room(x+1, y))
+			merged := row_sets[x+1]
+			for i := range row_sets {
+				if row_sets[i] == merged {
+					row_sets[i] = row_sets[x]
+				}
+			}
+		}
+		if last_row {
+			break
+		}
+
+		members := make(map[int][]int)
+		for x, set := range row_sets {
+			members[set] = append(members[set], x)
+		}
+		next_row_sets := make([]int, g.width)
+		for x := range row_sets {
+			set_members := members[row_sets[x]]
+			if set_members == nil {
+				continue
+			}
+			// every set goes down at least once
+			rng.Shuffle(len(set_members), func(i, j int) { set_members[i], set_members[j] = set_members[j], set_members[i] })
+			for i, member := range set_members {
+				if i == 0 || rng.Intn(2) == 0 {
+					g.connect(g.room(member, y), g.room(member, y+1))
+					next_row_sets[member] = row_sets[member]
+				}
+			}
+			members[row_sets[x]] = nil
+		}
+		row_sets = next_row_sets
+	}
+
+	return g.finishRows(ctx, rng)
+}
+
+// Carve runs of rooms along every row and connect a random room of each run to the row above it
+func (Sidewinder) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	for y := 0; y < g.length; y++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		top_row := y == g.length-1
+		run_start := 0
+		for x := 0; x < g.width; x++ {
+			g.carve(g.room(x, y))
+			if x < g.width-1 && (top_row || rng.Intn(2) == 0) {
+				g.connect(g.room(x, y), g.room(x+1, y))
+				continue
+			}
+			if !top_row {
+				member := run_start + rng.Intn(x-run_start+1)
+				g.connect(g.room(member, y), g.room(member, y+1))
+			}
+			run_start = x + 1
+		}
+	}
+
+	return g.finishRows(ctx, rng)
+}
+
+// Connect every room to the room above or to the right of it, whichever is available
+func (BinaryTree) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	for y := 0; y < g.length; y++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for x := 0; x < g.width; x++ {
+			g.carve(g.room(x, y))
+			options := make([]int, 0, 2)
+			if y < g.length-1 {
+				options = append(options, g.room(x, y+1))
+			}
+			if x < g.width-1 {
+				options = append(options, g.room(x+1, y))
+			}
+			if len(options) > 0 {
+				g.connect(g.room(x, y), options[rng.Intn(len(options))])
+			}
+		}
+	}
+
+	return g.finishRows(ctx, rng)
+}
+
+// Connect the rooms the rows left unreachable and attach start and finish to them
+// Context is checked around attaching, so that a generation past its deadline does not return as finished
+func (g roomGrid) finishRows(ctx context.Context, rng *rand.Rand) error {
+	g.connectUnreachable(rng)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.attachStartAndFinish(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
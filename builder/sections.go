@@ -0,0 +1,181 @@
+package builder
+
+import (
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Parts of the labyrinth between the checkpoints, numbered from 0 for the one with the start to the amount of checkpoints for the one with the finish
+// Cells of different sections never touch, so the finish cannot be reached without going through every checkpoint in order
+type routeSections struct {
+	f           *core.Field
+	sections    []int                    // section of every carved cell by cell index, -1 for the cells that were not carved
+	checkpoints map[core.Coordinates]int // number of every checkpoint, starting with 1
+}
+
+// Create sections of the field with checkpoints, there are no sections without them
+func newRouteSections(f *core.Field) *routeSections {
+	if len(f.Checkpoints) == 0 {
+		return nil
+	}
+
+	s := &routeSections{f: f, sections: make([]int, f.Size()), checkpoints: make(map[core.Coordinates]int, len(f.Checkpoints))}
+	for i := range s.sections {
+		s.sections[i] = -1
+	}
+	for i, checkpoint := range f.Checkpoints {
+		s.checkpoints[checkpoint] = i + 1
+	}
+
+	return s
+}
+
+// First and last sections the cell belongs to, checkpoints belong to the sections on both sides of them
+// Returns false if the cell does not belong to any section yet
+func (s *routeSections) rangeOf(c core.Coordinates) (int, int, bool) {
+	if !c.IsValid(s.f.Width-1, s.f.Length-1) {
+		return 0, 0, false
+	}
+	if section := s.sections[cellIndex(s.f, c)]; section != -1 {
+		return section, section, true
+	}
+	switch cell, _ := s.f.At(c); cell {
+	case core.Start:
+		return 0, 0, true
+	case core.Finish:
+		return len(s.checkpoints), len(s.checkpoints), true
+	case core.Checkpoint:
+		return s.checkpoints[c] - 1, s.checkpoints[c], true
+	default:
+		return 0, 0, false
+	}
+}
+
+// Section of the routes going on from the cell, they leave checkpoints towards the next one
+// Returns -1 for the cells that do not belong to any section, there is only the section 0 without checkpoints
+func (s *routeSections) of(c core.Coordinates) int {
+	if s == nil {
+		return 0
+	}
+	if _, last, ok := s.rangeOf(c); ok {
+		return last
+	}
+
+	return -1
+}
+
+// Check that the cell can be carved as a part of the section, all walkable cells next to it have to belong to the same section
+func (s *routeSections) allows(c core.Coordinates, section int) bool {
+	if s == nil {
+		return true
+	}
+	for _, shift := range NeumannShifts {
+		if first, last, ok := s.rangeOf(core.Coordinates{X: c.X + shift[0], Y: c.Y + shift[1]}); ok && (section < first || section > last) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Leave out the choices that would touch other sections than the one of the cell they are made from
+func (s *routeSections) filter(choices []core.Coordinates, from core.Coordinates) []core.Coordinates {
+	if s == nil {
+		return choices
+	}
+	section := s.of(from)
+	allowed := choices[:0]
+	for _, choice := range choices {
+		if s.allows(choice, section) {
+			allowed = append(allowed, choice)
+		}
+	}
+
+	return allowed
+}
+
+// Record the section of the carved cell
+func (s *routeSections) mark(c core.Coordinates, section int) {
+	if s != nil {
+		s.sections[cellIndex(s.f, c)] = section
+	}
+}
+
+// Forget the section of the cell that became empty again
+func (s *routeSections) unmark(c core.Coordinates) {
+	if s != nil {
+		s.sections[cellIndex(s.f, c)] = -1
+	}
+}
+
+// Find distances from the target to every empty cell that can be carved as a part of the section
+func (s *routeSections) distancesTo(target core.Coordinates, section int) []int {
+	return distancesFrom(s.f, target, func(c core.Coordinates) bool {
+		cell, err := s.f.At(c)
+		return err == nil && cell == core.Empty && s.allows(c, section)
+	})
+}
+
+// Plan the shortest way from the cell to the target down the distances to it, the cell and the target are left out
+// The cell itself needs no distance, e.g. if the route already ends in it, returns nil if none of its neighbors have one
+func (s *routeSections) plan(from core.Coordinates, distances []int) []core.Coordinates {
+	plan := make([]core.Coordinates, 0)
+	for coords := from; ; {
+		next, closest := core.Coordinates{}, -1
+		for _, shift := range NeumannShifts {
+			neighbor := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
+			if neighbor.IsValid(s.f.Width-1, s.f.Length-1) && distances[cellIndex(s.f, neighbor)] != -1 && (closest == -1 || distances[cellIndex(s.f, neighbor)] < closest) {
+				next, closest = neighbor, distances[cellIndex(s.f, neighbor)]
+			}
+		}
+		switch closest {
+		case -1:
+			return nil
+		case 0:
+			return plan
+		}
+		plan = append(plan, next)
+		coords = next
+	}
+}
+
+// Check that the goals after the target can still be reached once the planned cells are carved as a part of the section
+func (s *routeSections) fits(plan []core.Coordinates, target core.Coordinates, section int, goals []core.Coordinates) bool {
+	for _, coords := range plan {
+		s.f.Set(core.Path, coords)
+		s.mark(coords, section)
+	}
+	fits := s.keepsGoalsReachable(target, section, goals)
+	for _, coords := range plan {
+		s.f.Set(core.Empty, coords)
+		s.unmark(coords)
+	}
+
+	return fits
+}
+
+// Check that every goal after the target can still be reached from the previous one through empty cells of the section between them
+// The shortest way to every goal is carved for a while, so that the ways to the later goals cannot cross it
+func (s *routeSections) keepsGoalsReachable(target core.Coordinates, section int, goals []core.Coordinates) bool {
+	carved := make([]core.Coordinates, 0)
+	defer func() {
+		for _, coords := range carved {
+			s.f.Set(core.Empty, coords)
+			s.unmark(coords)
+		}
+	}()
+
+	for i, goal := range goals {
+		plan := s.plan(target, s.distancesTo(goal, section+i+1))
+		if plan == nil {
+			return false
+		}
+		for _, coords := range plan {
+			s.f.Set(core.Path, coords)
+			s.mark(coords, section+i+1)
+		}
+		carved = append(carved, plan...)
+		target = goal
+	}
+
+	return true
+}
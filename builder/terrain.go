@@ -0,0 +1,32 @@
+package builder
+
+import (
+	"math"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Cover a part of empty cells with terrain according to the configuration
+func scatterTerrain(f *core.Field, rng *rand.Rand) {
+	kinds := f.Configuration.Terrain.Cells()
+	if len(kinds) == 0 || f.Configuration.Terrain.Density == 0 {
+		return
+	}
+
+	empty_cells := make([]core.Coordinates, 0)
+	for y := 0; y < int(f.Length); y++ {
+		for x := 0; x < int(f.Width); x++ {
+			coords := core.Coordinates{X: x, Y: y}
+			if cell, err := f.At(coords); err == nil && cell == core.Empty {
+				empty_cells = append(empty_cells, coords)
+			}
+		}
+	}
+
+	rng.Shuffle(len(empty_cells), func(i, j int) { empty_cells[i], empty_cells[j] = empty_cells[j], empty_cells[i] })
+	cells_to_cover := int(math.Round(float64(len(empty_cells)) * f.Configuration.Terrain.Density / 100))
+	for _, coords := range empty_cells[:cells_to_cover] {
+		f.Set(kinds[rng.Intn(len(kinds))], coords)
+	}
+}
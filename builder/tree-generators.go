@@ -0,0 +1,177 @@
+package builder
+
+import (
+	"context"
+	"math/rand"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Depth-first carving that backtracks from dead ends, produces long winding corridors with few branches
+type RecursiveBacktracker struct{}
+
+// Randomized Prim's algorithm, grows the labyrinth from one room and produces many short dead ends
+type Prim struct{}
+
+// Randomized Kruskal's algorithm, joins random neighboring rooms from different parts of the labyrinth
+type Kruskal struct{}
+
+// Wilson's algorithm, adds loop-erased random walks to the labyrinth, every labyrinth is equally likely
+type Wilson struct{}
+
+// Aldous-Broder algorithm, walks randomly and connects every room on the first visit, every labyrinth is equally likely
+type AldousBroder struct{}
+
+// Carve the labyrinth depth-first starting from the room of the start cell
+func (RecursiveBacktracker) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	visited := make([]bool, g.size())
+	stack := []int{g.roomAt(f.Start)}
+	visited[stack[0]] = true
+	g.carve(stack[0])
+
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		room := stack[len(stack)-1]
+		unvisited := make([]int, 0, len(NeumannShifts))
+		for _, neighbor := range g.neighbors(room) {
+			if !visited[neighbor] {
+				unvisited = append(unvisited, neighbor)
+			}
+		}
+		if len(unvisited) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := unvisited[rng.Intn(len(unvisited))]
+		visited[next] = true
+		g.connect(room, next)
+		stack = append(stack, next)
+	}
+	return g.attachStartAndFinish()
+}
+
+// Grow the labyrinth from the room of the start cell, connecting a random room next to it on every step
+func (Prim) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	in_labyrinth, in_frontier := make([]bool, g.size()), make([]bool, g.size())
+	frontier := []int{}
+	addRoom := func(room int) {
+		in_labyrinth[room] = true
+		g.carve(room)
+		for _, neighbor := range g.neighbors(room) {
+			if !in_labyrinth[neighbor] && !in_frontier[neighbor] {
+				in_frontier[neighbor] = true
+				frontier = append(frontier, neighbor)
+			}
+		}
+	}
+	addRoom(g.roomAt(f.Start))
+
+	for len(frontier) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		idx := rng.Intn(len(frontier))
+		room := frontier[idx]
+		frontier[idx] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		connected := make([]int, 0, len(NeumannShifts))
+		for _, neighbor := range g.neighbors(room) {
+			if in_labyrinth[neighbor] {
+				connected = append(connected, neighbor)
+			}
+		}
+		g.connect(connected[rng.Intn(len(connected))], room)
+		addRoom(room)
+	}
+	return g.attachStartAndFinish()
+}
+
+// Connect neighboring rooms in random order unless they are already connected in some other way
+func (Kruskal) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	passages := make([][2]int, 0, g.size()*2)
+	for room := 0; room < g.size(); room++ {
+		g.carve(room)
+		for _, neighbor := range g.neighbors(room) {
+			if neighbor > room {
+				passages = append(passages, [2]int{room, neighbor})
+			}
+		}
+	}
+
+	sets := newRoomSets(g.size())
+	rng.Shuffle(len(passages), func(i, j int) { passages[i], passages[j] = passages[j], passages[i] })
+	for _, passage := range passages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if sets.union(passage[0], passage[1]) {
+			g.connect(passage[0], passage[1])
+		}
+	}
+	return g.attachStartAndFinish()
+}
+
+// Walk randomly from every room outside of the labyrinth until it is reached, then carve the walk without its loops
+func (Wilson) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	in_labyrinth := make([]bool, g.size())
+	for room, usable := range g.usable {
+		// rooms that cannot be reached are left out as if they were already a part of the labyrinth
+		in_labyrinth[room] = !usable
+	}
+	first := g.roomAt(f.Start)
+	in_labyrinth[first] = true
+	g.carve(first)
+
+	next_step := make([]int, g.size())
+	for _, room := range rng.Perm(g.size()) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// every room remembers the last way out of it, which erases the loops of the walk
+		for curr := room; !in_labyrinth[curr]; curr = next_step[curr] {
+			// the walk can wander over the whole grid many times before it reaches the labyrinth
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			neighbors := g.neighbors(curr)
+			next_step[curr] = neighbors[rng.Intn(len(neighbors))]
+		}
+		for curr := room; !in_labyrinth[curr]; curr = next_step[curr] {
+			in_labyrinth[curr] = true
+			g.connect(curr, next_step[curr])
+		}
+	}
+	return g.attachStartAndFinish()
+}
+
+// Walk randomly through the rooms and connect each of them to the previous one when it is entered for the first time
+func (AldousBroder) Generate(ctx context.Context, f *core.Field, rng *rand.Rand, attempt *Attempt) error {
+	g := newRoomGrid(f)
+	visited := make([]bool, g.size())
+	room := g.roomAt(f.Start)
+	visited[room] = true
+	g.carve(room)
+
+	for left := g.usableSize() - 1; left > 0; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		neighbors := g.neighbors(room)
+		next := neighbors[rng.Intn(len(neighbors))]
+		if !visited[next] {
+			visited[next] = true
+			g.connect(room, next)
+			left--
+		}
+		room = next
+	}
+	return g.attachStartAndFinish()
+}
@@ -10,6 +10,16 @@ const (
 	Start
 	Finish
 	Path
+	Sand
+	Mud
+	Water
+	RedKey
+	GreenKey
+	BlueKey
+	RedDoor
+	GreenDoor
+	BlueDoor
+	Checkpoint
 	Unknown // should always be last for type validation
 )
 
@@ -26,14 +36,108 @@ func (c cell) String() string {
 		return "f"
 	case Path:
 		return "x"
+	case Sand:
+		return "░"
+	case Mud:
+		return "▒"
+	case Water:
+		return "≈"
+	case RedKey:
+		return "r"
+	case GreenKey:
+		return "g"
+	case BlueKey:
+		return "b"
+	case RedDoor:
+		return "R"
+	case GreenDoor:
+		return "G"
+	case BlueDoor:
+		return "B"
+	case Checkpoint:
+		return "c"
 	default:
 		return "?"
 	}
 }
 
 // Check if the cell cannot be a part of the route
+// Checkpoints are blocking as well, the route heading to one of them enters it on purpose
 func (c cell) IsBlocking(finish_is_blocking bool) bool {
-	return c == Wall || c == Path || c == Start || c == Finish && finish_is_blocking
+	return c == Wall || c == Path || c == Start || c == Checkpoint || c == Finish && finish_is_blocking
+}
+
+// Check if the cell can be walked through when solving the labyrinth
+// Doors are not walkable, as they need a key to be opened
+func (c cell) IsWalkable() bool {
+	return c != Wall && c < Unknown && !c.IsDoor()
+}
+
+// Check if the cell is a plain passage, which tells nothing more than that it can be walked through
+// Only such cells are covered when a route is drawn, so that start, finish, checkpoints, keys, doors and terrain stay visible
+func (c cell) isPlainPassage() bool {
+	return c == Empty || c == Path
+}
+
+// Amount of different colours of keys and doors
+const KeyColours = 3
+
+// Key cell of the chosen colour
+func KeyCell(colour uint) cell {
+	return RedKey + cell(colour)
+}
+
+// Door cell of the chosen colour
+func DoorCell(colour uint) cell {
+	return RedDoor + cell(colour)
+}
+
+// Check if the cell is a key of any colour
+func (c cell) IsKey() bool {
+	return c >= RedKey && c <= BlueKey
+}
+
+// Check if the cell is a door of any colour
+func (c cell) IsDoor() bool {
+	return c >= RedDoor && c <= BlueDoor
+}
+
+// Colour of the key or the door in the cell
+func (c cell) Colour() uint {
+	switch {
+	case c.IsKey():
+		return uint(c - RedKey)
+	case c.IsDoor():
+		return uint(c - RedDoor)
+	default:
+		return 0
+	}
+}
+
+// Check if the cell is a terrain that takes more effort to walk through
+func (c cell) IsTerrain() bool {
+	return c == Sand || c == Mud || c == Water
+}
+
+// Amount of effort needed to walk through the cell
+func (c cell) Cost() uint {
+	switch c {
+	case Sand:
+		return 2
+	case Mud:
+		return 3
+	case Water:
+		return 5
+	default:
+		return 1
+	}
+}
+
+// Names of terrain cells used in configuration
+var terrainNames = map[string]cell{
+	"sand":  Sand,
+	"mud":   Mud,
+	"water": Water,
 }
 
 // Create a string representation of a labyrinth row
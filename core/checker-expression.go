@@ -0,0 +1,144 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Checker used if the expression is empty
+const DefaultChoiceChecker = "2-close-blocks"
+
+// Parsed checker expression, either a checker name or an operator with its operands
+type CheckerExpression struct {
+	Operator string // 'and', 'or', 'not', or empty for a checker name
+	Name     string
+	Operands []CheckerExpression
+}
+
+// Custom error for CheckerExpression
+func (CheckerExpression) Error(s string) error {
+	return fmt.Errorf("Checker expression error: %v", s)
+}
+
+// Check that the expression uses the checker with the name anywhere in it
+func (e CheckerExpression) Uses(name string) bool {
+	if e.Operator == "" {
+		return e.Name == name
+	}
+	for _, operand := range e.Operands {
+		if operand.Uses(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parser of the checker expressions, keeps the tokens that are left to read
+type checkerParser struct {
+	tokens []string
+}
+
+// Split the expression into names, operators and parentheses
+func tokenizeChecker(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+
+	return strings.Fields(strings.ToLower(expression))
+}
+
+// Get the next token without reading it, empty string means the end of the expression
+func (p *checkerParser) peek() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+
+	return p.tokens[0]
+}
+
+// Read the next token
+func (p *checkerParser) next() string {
+	token := p.peek()
+	if len(p.tokens) > 0 {
+		p.tokens = p.tokens[1:]
+	}
+
+	return token
+}
+
+// Parse the operands joined with the operator, parsing every one of them with the given function
+func (p *checkerParser) parseJoined(operator string, parse func() (CheckerExpression, error)) (CheckerExpression, error) {
+	operand, err := parse()
+	if err != nil {
+		return CheckerExpression{}, err
+	}
+	operands := []CheckerExpression{operand}
+	for p.peek() == operator {
+		p.next()
+		if operand, err = parse(); err != nil {
+			return CheckerExpression{}, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+
+	return CheckerExpression{Operator: operator, Operands: operands}, nil
+}
+
+// Parse the checkers joined with 'or', which binds weaker than 'and'
+func (p *checkerParser) parseOr() (CheckerExpression, error) {
+	return p.parseJoined("or", p.parseAnd)
+}
+
+// Parse the checkers joined with 'and'
+func (p *checkerParser) parseAnd() (CheckerExpression, error) {
+	return p.parseJoined("and", p.parseNot)
+}
+
+// Parse a checker name, an expression in parentheses or either of them preceded by 'not'
+func (p *checkerParser) parseNot() (CheckerExpression, error) {
+	switch token := p.next(); token {
+	case "not":
+		operand, err := p.parseNot()
+		if err != nil {
+			return CheckerExpression{}, err
+		}
+		return CheckerExpression{Operator: "not", Operands: []CheckerExpression{operand}}, nil
+	case "(":
+		expression, err := p.parseOr()
+		if err != nil {
+			return CheckerExpression{}, err
+		}
+		if p.next() != ")" {
+			return CheckerExpression{}, expression.Error("missing closing parenthesis")
+		}
+		return expression, nil
+	case "":
+		return CheckerExpression{}, CheckerExpression{}.Error("expression ended unexpectedly")
+	case "and", "or", ")":
+		return CheckerExpression{}, CheckerExpression{}.Error(fmt.Sprintf("unexpected '%v'", token))
+	default:
+		return CheckerExpression{Name: token}, nil
+	}
+}
+
+// Parse the expression of checker names joined with 'and', 'or', 'not' and parentheses,
+// e.g. "corners and not (n-blocks or 2-close-blocks)". The default checker is used if the expression is empty
+// Only the syntax is checked, the builder owns the checkers and rejects the names it does not know
+func ParseCheckerExpression(expression string) (CheckerExpression, error) {
+	p := checkerParser{tokens: tokenizeChecker(expression)}
+	if len(p.tokens) == 0 {
+		return CheckerExpression{Name: DefaultChoiceChecker}, nil
+	}
+
+	parsed, err := p.parseOr()
+	if err != nil {
+		return CheckerExpression{}, err
+	}
+	if len(p.tokens) > 0 {
+		return CheckerExpression{}, parsed.Error(fmt.Sprintf("unexpected '%v'", p.peek()))
+	}
+
+	return parsed, nil
+}
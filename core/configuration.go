@@ -10,13 +10,58 @@ import (
 // configuration structure
 type (
 	builder struct {
+		Algorithm               string  `toml:"algorithm"`
 		Complexity              float64 `toml:"complexity"`
+		Straightness            float64 `toml:"straightness"`
+		HorizontalBias          float64 `toml:"horizontal_bias"`
 		MaxAreaToCoverWithWalls float64 `toml:"max_area_to_cover_with_walls"`
 		OnlyOnePathNearFinish   bool    `toml:"only_one_path_near_finish"`
+		Placement               string  `toml:"placement"`
+		Checker                 string  `toml:"checker"`
+		MaxBlocksAround         uint    `toml:"max_blocks_around"`
 		LabyrinthBuilderAtempts uint    `toml:"labyrinth_builder_atempts"`
+		ParallelAttempts        uint    `toml:"parallel_attempts"`
+		ParallelPick            string  `toml:"parallel_pick"`
+		Seed                    int64   `toml:"seed"`
+	}
+	terrain struct {
+		Density float64  `toml:"density"`
+		Kinds   []string `toml:"kinds"`
+	}
+	puzzle struct {
+		KeysAndDoors uint `toml:"keys_and_doors"`
+	}
+	difficulty struct {
+		MinSolutionLength uint     `toml:"min_solution_length"`
+		MaxSolutionLength *uint    `toml:"max_solution_length"` // nil when there is no upper bound
+		MinDeadEnds       uint     `toml:"min_dead_ends"`
+		MaxDeadEnds       *uint    `toml:"max_dead_ends"` // nil when there is no upper bound
+		MinBranching      float64  `toml:"min_branching"`
+		MaxBranching      *float64 `toml:"max_branching"` // nil when there is no upper bound
+		Attempts          uint     `toml:"attempts"`
+	}
+	mask struct {
+		Shape string `toml:"shape"`
+		File  string `toml:"file"`
+		Text  string `toml:"text"`
+	}
+	braid struct {
+		Percentage float64 `toml:"percentage"`
+	}
+	dungeon struct {
+		Rooms            uint `toml:"rooms"`
+		MinRoomSize      uint `toml:"min_room_size"`
+		MaxRoomSize      uint `toml:"max_room_size"`
+		ExtraConnections uint `toml:"extra_connections"`
 	}
 	configuration struct {
-		Builder builder `toml:"builder"`
+		Builder    builder    `toml:"builder"`
+		Terrain    terrain    `toml:"terrain"`
+		Puzzle     puzzle     `toml:"puzzle"`
+		Difficulty difficulty `toml:"difficulty"`
+		Mask       mask       `toml:"mask"`
+		Braid      braid      `toml:"braid"`
+		Dungeon    dungeon    `toml:"dungeon"`
 	}
 )
 
@@ -30,13 +75,99 @@ func (c *configuration) validate() error {
 	if c.Builder.Complexity < 0 || c.Builder.Complexity > 100 {
 		return c.Error("Complexity (percentage) cannot be less than 0 or over 100")
 	}
+	if c.Builder.Straightness < -100 || c.Builder.Straightness > 100 {
+		return c.Error("Straightness (percentage) cannot be less than -100 or over 100")
+	}
+	if c.Builder.HorizontalBias < -100 || c.Builder.HorizontalBias > 100 {
+		return c.Error("Horizontal bias (percentage) cannot be less than -100 or over 100")
+	}
 	if c.Builder.MaxAreaToCoverWithWalls <= 0 || c.Builder.MaxAreaToCoverWithWalls > 100 {
 		return c.Error("Max area to cover with walls (percentage) cannot be less or equal to 0 or over 1")
 	}
+	switch c.Builder.Placement {
+	case "", "random-border", "opposite-edges", "farthest-pair":
+	default:
+		return c.Error(fmt.Sprintf("Unknown start and finish placement '%v'", c.Builder.Placement))
+	}
+	switch c.Builder.ParallelPick {
+	case "", "first", "longest-solution", "most-dead-ends":
+	default:
+		return c.Error(fmt.Sprintf("Unknown metric to pick parallel attempts by '%v'", c.Builder.ParallelPick))
+	}
+	if _, err := ParseCheckerExpression(c.Builder.Checker); err != nil {
+		return err
+	}
+	if c.Builder.MaxBlocksAround > 8 {
+		return c.Error("Max blocks around cannot be over 8, the size of Moore's neighborhood")
+	}
+	if c.Terrain.Density < 0 || c.Terrain.Density > 100 {
+		return c.Error("Terrain density (percentage) cannot be less than 0 or over 100")
+	}
+	if c.Terrain.Density > 0 && len(c.Terrain.Kinds) == 0 {
+		return c.Error("Terrain kinds should be listed if terrain density is above 0")
+	}
+	for _, kind := range c.Terrain.Kinds {
+		if _, ok := terrainNames[kind]; !ok {
+			return c.Error(fmt.Sprintf("Unknown terrain kind '%v'", kind))
+		}
+	}
+	if c.Puzzle.KeysAndDoors > KeyColours {
+		return c.Error(fmt.Sprintf("Keys and doors cannot have more than %v pairs, one for each colour", KeyColours))
+	}
+	if d := c.Difficulty; d.MaxSolutionLength != nil && d.MinSolutionLength > *d.MaxSolutionLength ||
+		d.MaxDeadEnds != nil && d.MinDeadEnds > *d.MaxDeadEnds ||
+		d.MaxBranching != nil && d.MinBranching > *d.MaxBranching {
+		return c.Error("Minimal difficulty values cannot be over the maximal ones")
+	}
+	if c.Difficulty.MinBranching < 0 || c.Difficulty.MaxBranching != nil && *c.Difficulty.MaxBranching < 0 {
+		return c.Error("Branching cannot be negative")
+	}
+	if c.Difficulty.IsSet() && c.Difficulty.Attempts == 0 {
+		return c.Error("Difficulty attempts should be positive if any difficulty range is set")
+	}
+	if c.Braid.Percentage < 0 || c.Braid.Percentage > 100 {
+		return c.Error("Braid percentage cannot be less than 0 or over 100")
+	}
+	if c.Builder.Algorithm == "dungeon" && c.Dungeon.Rooms == 0 {
+		return c.Error("Dungeon should have at least one room")
+	}
+	if c.Dungeon.Rooms > 0 && (c.Dungeon.MinRoomSize == 0 || c.Dungeon.MinRoomSize > c.Dungeon.MaxRoomSize) {
+		return c.Error("Minimal room size should be positive and cannot be over the maximal one")
+	}
+	switch c.Mask.Shape {
+	case "", "circle", "triangle":
+	case "file":
+		if c.Mask.File == "" {
+			return c.Error("Mask file should be set for the 'file' mask shape")
+		}
+	case "text":
+		if c.Mask.Text == "" {
+			return c.Error("Mask text should be set for the 'text' mask shape")
+		}
+	default:
+		return c.Error(fmt.Sprintf("Unknown mask shape '%v'", c.Mask.Shape))
+	}
 
 	return nil
 }
 
+// Get terrain cells listed in the configuration
+func (t terrain) Cells() []cell {
+	cells := make([]cell, 0, len(t.Kinds))
+	for _, kind := range t.Kinds {
+		if terrain_cell, ok := terrainNames[kind]; ok {
+			cells = append(cells, terrain_cell)
+		}
+	}
+
+	return cells
+}
+
+// Check that at least one of the difficulty ranges is limited, a maximum of 0 is a limit too
+func (d difficulty) IsSet() bool {
+	return d.MinSolutionLength > 0 || d.MaxSolutionLength != nil || d.MinDeadEnds > 0 || d.MaxDeadEnds != nil || d.MinBranching > 0 || d.MaxBranching != nil
+}
+
 // Load configuration values from TOML file under 'filename'
 func (c *configuration) LoadFromFile(filename string) error {
 	if blob, err := os.ReadFile(filename); err != nil {
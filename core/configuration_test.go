@@ -0,0 +1,100 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Load the default configuration with some of its lines replaced, given as pairs of the old and the new line
+func loadChangedConfiguration(t *testing.T, replacements ...string) (*configuration, error) {
+	t.Helper()
+	text, err := os.ReadFile("../config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := string(text)
+	for i := 0; i+1 < len(replacements); i += 2 {
+		if !strings.Contains(changed, replacements[i]) {
+			t.Fatalf("default configuration has no %q", replacements[i])
+		}
+		changed = strings.Replace(changed, replacements[i], replacements[i+1], 1)
+	}
+	file_path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(file_path, []byte(changed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var config configuration
+	err = config.LoadFromFile(file_path)
+
+	return &config, err
+}
+
+// Difficulty maxima left out of configuration are not limits, while the ones set to 0 are
+func TestDifficultyMaxima(t *testing.T) {
+	config, err := loadChangedConfiguration(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Difficulty.IsSet() || config.Difficulty.MaxDeadEnds != nil {
+		t.Errorf("default configuration has difficulty limits: %+v", config.Difficulty)
+	}
+
+	config, err = loadChangedConfiguration(t, "# max_dead_ends = 10", "max_dead_ends = 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.Difficulty.IsSet() || config.Difficulty.MaxDeadEnds == nil || *config.Difficulty.MaxDeadEnds != 0 {
+		t.Errorf("maximum of 0 dead ends is not a limit: %+v", config.Difficulty)
+	}
+
+	if _, err := loadChangedConfiguration(t, "# max_solution_length = 100", "max_solution_length = 5", "min_solution_length = 0", "min_solution_length = 10"); err == nil {
+		t.Error("minimum over the maximum was accepted")
+	}
+}
+
+// Checker expressions are parsed as soon as configuration is loaded, the names are left for the builder to check
+func TestCheckerExpression(t *testing.T) {
+	cases := []struct {
+		replacements []string
+		valid        bool
+	}{
+		{[]string{`checker = "2-close-blocks"`, `checker = "corners and not (n-blocks or 2-close-blocks)"`}, true},
+		{[]string{`checker = "2-close-blocks"`, `checker = "2-close-block"`}, true},
+		{[]string{`checker = "2-close-blocks"`, `checker = "corners or"`}, false},
+		{[]string{`checker = "2-close-blocks"`, `checker = "corners and or n-blocks"`}, false},
+		{[]string{`checker = "2-close-blocks"`, `checker = "(corners))"`}, false},
+		{[]string{`checker = "2-close-blocks"`, `checker = "n-blocks"`}, true},
+	}
+	for _, c := range cases {
+		if _, err := loadChangedConfiguration(t, c.replacements...); c.valid && err != nil {
+			t.Errorf("%q: %v", c.replacements, err)
+		} else if !c.valid && err == nil {
+			t.Errorf("%q: expected an error", c.replacements)
+		}
+	}
+}
+
+// Parsed expressions keep the operators binding the way they are written, 'and' binds stronger than 'or'
+func TestParseCheckerExpression(t *testing.T) {
+	parsed, err := ParseCheckerExpression("corners or not n-blocks and 2-close-blocks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := CheckerExpression{Operator: "or", Operands: []CheckerExpression{
+		{Name: "corners"},
+		{Operator: "and", Operands: []CheckerExpression{
+			{Operator: "not", Operands: []CheckerExpression{{Name: "n-blocks"}}},
+			{Name: "2-close-blocks"},
+		}},
+	}}
+	if fmt.Sprint(parsed) != fmt.Sprint(expected) {
+		t.Errorf("expected %+v, got %+v", expected, parsed)
+	}
+	if empty, err := ParseCheckerExpression(" "); err != nil || empty.Name != DefaultChoiceChecker {
+		t.Errorf("empty expression gives %+v, %v", empty, err)
+	}
+}
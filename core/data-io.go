@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -23,13 +24,20 @@ func checkFilePath(file_path string, is_folder bool) error {
 	return nil
 }
 
-// Save serialized labyrinth data to file in existing directory
+// Labyrinth data as it is saved to file
+type savedLabyrinth struct {
+	Labyrinth   [][]uint      `json:"labyrinth"`
+	Checkpoints []Coordinates `json:"checkpoints,omitempty"` // in the order they should be visited in
+	Seed        int64         `json:"seed,omitempty"`
+}
+
+// Save serialized labyrinth data together with the order of checkpoints and the seed to file in existing directory
 func (f *Field) SaveLabyrinthToFile(file_path string) error {
 	if err := checkFilePath(file_path, true); err != nil {
 		return f.Error(err.Error())
 	}
 
-	serialized_data, err := json.Marshal(f.GetLabyrinth())
+	serialized_data, err := json.Marshal(savedLabyrinth{Labyrinth: f.GetLabyrinth(), Checkpoints: f.Checkpoints, Seed: f.Seed})
 	if err != nil {
 		return f.Error(err.Error())
 	}
@@ -41,7 +49,8 @@ func (f *Field) SaveLabyrinthToFile(file_path string) error {
 	return nil
 }
 
-// Deserialize and load labyrinth data from file
+// Deserialize and load labyrinth data from file, files with only the array of cells are loaded too
+// Checkpoints of such files are listed row by row, as their order is unknown
 func (f *Field) LoadLabyrinthFromFile(file_path string) error {
 	if err := checkFilePath(file_path, false); err != nil {
 		return f.Error(err.Error())
@@ -52,14 +61,31 @@ func (f *Field) LoadLabyrinthFromFile(file_path string) error {
 		return f.Error(err.Error())
 	}
 
-	var deserialized_data [][]uint
+	var deserialized_data savedLabyrinth
 	if err := json.Unmarshal(serialized_data, &deserialized_data); err != nil {
-		return f.Error(err.Error())
+		// files saved before the seed was stored hold nothing but the cells
+		if err := json.Unmarshal(serialized_data, &deserialized_data.Labyrinth); err != nil {
+			return f.Error(err.Error())
+		}
 	}
 
-	if err := f.LoadLabyrinth(deserialized_data); err != nil {
+	if err := f.LoadLabyrinth(deserialized_data.Labyrinth); err != nil {
 		return err
 	}
+	if len(deserialized_data.Checkpoints) > 0 {
+		if len(deserialized_data.Checkpoints) != len(f.Checkpoints) {
+			return f.Error(fmt.Sprintf("File lists %v checkpoints, but the labyrinth has %v", len(deserialized_data.Checkpoints), len(f.Checkpoints)))
+		}
+		for _, checkpoint := range deserialized_data.Checkpoints {
+			if cell, err := f.At(checkpoint); err != nil || cell != Checkpoint {
+				return f.Error(fmt.Sprintf("File lists checkpoint %v, but the labyrinth has no checkpoint there", checkpoint))
+			}
+		}
+		if err := f.SetCheckpoints(deserialized_data.Checkpoints...); err != nil {
+			return err
+		}
+	}
+	f.Seed = deserialized_data.Seed
 
 	return nil
 }
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Walls of the small labyrinth that is saved and loaded, between start and finish
+var savedWalls = []Coordinates{{X: 1, Y: 0}, {X: 1, Y: 1}}
+
+// Saved labyrinth is loaded with the same cells and seed
+func TestSaveAndLoadLabyrinth(t *testing.T) {
+	f := newTestField(4, 3, savedWalls...)
+	f.Seed = 42
+	file_path := filepath.Join(t.TempDir(), "labyrinth.json")
+	if err := f.SaveLabyrinthToFile(file_path); err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Field
+	if err := loaded.LoadLabyrinthFromFile(file_path); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.GetLabyrinth(), f.GetLabyrinth()) {
+		t.Errorf("loaded labyrinth differs from the saved one\n%v\n%v", loaded, f)
+	}
+	if loaded.Seed != f.Seed || loaded.Start != f.Start || loaded.Finish != f.Finish {
+		t.Errorf("loaded seed %v, start %v and finish %v, saved %v, %v and %v", loaded.Seed, loaded.Start, loaded.Finish, f.Seed, f.Start, f.Finish)
+	}
+}
+
+// Files with only the array of cells are still loaded, without a seed
+func TestLoadLabyrinthWithoutSeed(t *testing.T) {
+	f := newTestField(4, 3, savedWalls...)
+	serialized_data, err := json.Marshal(f.GetLabyrinth())
+	if err != nil {
+		t.Fatal(err)
+	}
+	file_path := filepath.Join(t.TempDir(), "labyrinth.json")
+	if err := os.WriteFile(file_path, serialized_data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := Field{Seed: 7}
+	if err := loaded.LoadLabyrinthFromFile(file_path); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.GetLabyrinth(), f.GetLabyrinth()) || loaded.Seed != 0 {
+		t.Errorf("loaded seed %v and labyrinth\n%v", loaded.Seed, loaded)
+	}
+	if err := loaded.LoadLabyrinthFromFile("../examples/16x16.json"); err != nil {
+		t.Error(err)
+	}
+}
+
+// Checkpoints are loaded in the order they were saved in, not row by row
+func TestSaveAndLoadCheckpointsOrder(t *testing.T) {
+	f := newTestField(4, 3, savedWalls...)
+	checkpoints := []Coordinates{{X: 2, Y: 2}, {X: 0, Y: 2}, {X: 2, Y: 0}}
+	if err := f.SetCheckpoints(checkpoints...); err != nil {
+		t.Fatal(err)
+	}
+	file_path := filepath.Join(t.TempDir(), "labyrinth.json")
+	if err := f.SaveLabyrinthToFile(file_path); err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Field
+	if err := loaded.LoadLabyrinthFromFile(file_path); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Checkpoints, checkpoints) || !loaded.CheckpointsOrderKnown() {
+		t.Errorf("loaded checkpoints %v with known order %v, saved %v", loaded.Checkpoints, loaded.CheckpointsOrderKnown(), checkpoints)
+	}
+}
+
+// Checkpoints of files with only the array of cells are found row by row and their order stays unknown until they are set
+func TestLoadCheckpointsWithoutOrder(t *testing.T) {
+	f := newTestField(4, 3, savedWalls...)
+	if err := f.SetCheckpoints(Coordinates{X: 2, Y: 2}, Coordinates{X: 0, Y: 2}); err != nil {
+		t.Fatal(err)
+	}
+	serialized_data, err := json.Marshal(f.GetLabyrinth())
+	if err != nil {
+		t.Fatal(err)
+	}
+	file_path := filepath.Join(t.TempDir(), "labyrinth.json")
+	if err := os.WriteFile(file_path, serialized_data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Field
+	if err := loaded.LoadLabyrinthFromFile(file_path); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []Coordinates{{X: 0, Y: 2}, {X: 2, Y: 2}}; !reflect.DeepEqual(loaded.Checkpoints, expected) || loaded.CheckpointsOrderKnown() {
+		t.Errorf("loaded checkpoints %v with known order %v, expected %v with unknown order", loaded.Checkpoints, loaded.CheckpointsOrderKnown(), expected)
+	}
+	if err := loaded.SetCheckpoints(f.Checkpoints...); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.CheckpointsOrderKnown() {
+		t.Errorf("order of checkpoints %v is unknown after setting them", loaded.Checkpoints)
+	}
+}
+
+// Files listing checkpoints that are not in the labyrinth are not loaded
+func TestLoadMismatchedCheckpoints(t *testing.T) {
+	f := newTestField(4, 3, savedWalls...)
+	if err := f.SetCheckpoints(Coordinates{X: 2, Y: 2}); err != nil {
+		t.Fatal(err)
+	}
+	for _, checkpoints := range [][]Coordinates{{{X: 0, Y: 2}}, {{X: 2, Y: 2}, {X: 0, Y: 2}}} {
+		serialized_data, err := json.Marshal(savedLabyrinth{Labyrinth: f.GetLabyrinth(), Checkpoints: checkpoints})
+		if err != nil {
+			t.Fatal(err)
+		}
+		file_path := filepath.Join(t.TempDir(), "labyrinth.json")
+		if err := os.WriteFile(file_path, serialized_data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var loaded Field
+		if err := loaded.LoadLabyrinthFromFile(file_path); err == nil {
+			t.Errorf("file listing checkpoints %v was loaded for the labyrinth with %v", checkpoints, f.Checkpoints)
+		}
+	}
+}
@@ -8,9 +8,16 @@ import (
 // Container for labyrinth and additional characteristics
 type Field struct {
 	labyrinth     [][]cell
+	overlay       map[Coordinates]cell // cells drawn on top of the labyrinth, only used for display
+	mask          Mask                 // usable area of the labyrinth, nil if every cell is usable
 	Width, Length uint
 	Start, Finish Coordinates
+	Checkpoints   []Coordinates // cells the solution has to go through in the listed order
+	Seed          int64         // seed of the random source the labyrinth was generated with, it gives the same labyrinth only with the same configuration
 	Configuration *configuration
+
+	// checkpoints were found row by row in the loaded cells, so the order they should be visited in is unknown
+	checkpoints_unordered bool
 }
 
 // Return serialized labyrinth data
@@ -34,6 +41,7 @@ func (f *Field) LoadLabyrinth(l [][]uint) error {
 	width, length := len(l[0]), len(l)
 	labyrinth := make([][]cell, len(l))
 	var start, finish *Coordinates
+	checkpoints := make([]Coordinates, 0)
 	for row_idx, row := range l {
 		if len(row) != width {
 			return f.Error(fmt.Sprintf("Array should be rectangular, first row had %v elements, and row #%v has %v", width, row_idx, len(row)))
@@ -50,6 +58,8 @@ func (f *Field) LoadLabyrinth(l [][]uint) error {
 				start = &Coordinates{X: cell_idx, Y: row_idx}
 			case Finish:
 				finish = &Coordinates{X: cell_idx, Y: row_idx}
+			case Checkpoint:
+				checkpoints = append(checkpoints, Coordinates{X: cell_idx, Y: row_idx})
 			}
 		}
 	}
@@ -58,6 +68,10 @@ func (f *Field) LoadLabyrinth(l [][]uint) error {
 	}
 
 	f.Width, f.Length, f.labyrinth, f.Start, f.Finish = uint(width), uint(length), labyrinth, *start, *finish
+	// the order of checkpoints is not stored with the cells, they are loaded row by row
+	f.Checkpoints, f.checkpoints_unordered = checkpoints, len(checkpoints) > 1
+	f.mask = nil
+	f.ClearOverlay()
 
 	return nil
 }
@@ -74,28 +88,37 @@ func (f *Field) Init(filename string) error {
 }
 
 // Change the size of labyrinth
-// Clears up all cells
+// Clears up all cells and the mask
 func (f *Field) SetSize(width, length uint) {
 	f.labyrinth = make([][]cell, length)
 	for i := range f.labyrinth {
 		f.labyrinth[i] = make([]cell, width)
 	}
 	f.Width, f.Length = width, length
+	f.mask = nil
 	f.MakeEmpty(false)
+	f.ClearOverlay()
 }
 
-// Clear up all cells except for start and finish if the flag is true
+// Clear up all cells except for start, finish and checkpoints if the flag is true, masked out cells stay walls
 func (f *Field) MakeEmpty(leave_start_and_finish bool) {
 	for i := range f.labyrinth {
 		for j := range f.labyrinth[i] {
 			f.labyrinth[i][j] = Empty
+			if f.mask != nil && !f.mask[i][j] {
+				f.labyrinth[i][j] = Wall
+			}
 		}
 	}
 	if leave_start_and_finish {
 		f.labyrinth[f.Start.Y][f.Start.X] = Start
 		f.labyrinth[f.Finish.Y][f.Finish.X] = Finish
+		for _, checkpoint := range f.Checkpoints {
+			f.labyrinth[checkpoint.Y][checkpoint.X] = Checkpoint
+		}
 	} else {
 		f.Start, f.Finish = Coordinates{-1, -1}, Coordinates{-1, -1}
+		f.Checkpoints = nil
 	}
 }
 
@@ -109,11 +132,12 @@ func (f *Field) At(c Coordinates) (cell, error) {
 }
 
 // Change cell type at the chosen coordinates
+// Start, finish, checkpoints and masked out cells stay unchanged
 func (f *Field) Set(new_cell cell, c Coordinates) error {
 	if !c.IsValid(f.Width-1, f.Length-1) {
 		return f.Error(fmt.Sprintf("Cannot set cell %v out of field's bounds w=%v l=%v", c, f.Width, f.Length))
 	}
-	if old_cell, _ := f.At(c); old_cell != Start && old_cell != Finish {
+	if old_cell, _ := f.At(c); old_cell != Start && old_cell != Finish && old_cell != Checkpoint && f.IsUsable(c) {
 		f.labyrinth[c.Y][c.X] = new_cell
 	}
 
@@ -127,12 +151,74 @@ func (f *Field) SetStartAndFinish(start, finish Coordinates) {
 	f.Start, f.Finish = start, finish
 }
 
+// Set start and finish points, the cells of the previous ones become empty unless they are reused
+func (f *Field) MoveStartAndFinish(start, finish Coordinates) {
+	f.ClearStartAndFinish()
+	f.SetStartAndFinish(start, finish)
+}
+
+// Remove start and finish points, their cells become empty
+func (f *Field) ClearStartAndFinish() {
+	for _, c := range []Coordinates{f.Start, f.Finish} {
+		if cell, err := f.At(c); err == nil && (cell == Start || cell == Finish) {
+			f.labyrinth[c.Y][c.X] = Empty
+		}
+	}
+	f.Start, f.Finish = Coordinates{-1, -1}, Coordinates{-1, -1}
+}
+
+// Set checkpoints the solution has to go through in the listed order, replacing the previous ones
+func (f *Field) SetCheckpoints(checkpoints ...Coordinates) error {
+	for i, checkpoint := range checkpoints {
+		if !checkpoint.IsValid(f.Width-1, f.Length-1) || !f.IsUsable(checkpoint) {
+			return f.Error(fmt.Sprintf("Checkpoint %v is out of field's bounds or masked out", checkpoint))
+		}
+		if checkpoint == f.Start || checkpoint == f.Finish {
+			return f.Error(fmt.Sprintf("Checkpoint %v cannot be placed on start or finish", checkpoint))
+		}
+		for _, other := range checkpoints[:i] {
+			if other == checkpoint {
+				return f.Error(fmt.Sprintf("Checkpoint %v is listed more than once", checkpoint))
+			}
+		}
+	}
+
+	for _, checkpoint := range f.Checkpoints {
+		f.labyrinth[checkpoint.Y][checkpoint.X] = Empty
+	}
+	f.Checkpoints, f.checkpoints_unordered = append([]Coordinates{}, checkpoints...), false
+	for _, checkpoint := range f.Checkpoints {
+		f.labyrinth[checkpoint.Y][checkpoint.X] = Checkpoint
+	}
+
+	return nil
+}
+
+// Check that the checkpoints are listed in the order they should be visited in,
+// which is not the case for several checkpoints loaded from the cells alone
+func (f *Field) CheckpointsOrderKnown() bool {
+	return !f.checkpoints_unordered
+}
+
 // String representation of the entire labyrinth and its data
 func (f Field) String() string {
-	field_string := fmt.Sprintf("Size: %vx%v\nStart: %v\nFinish: %v\n\n", f.Width, f.Length, f.Start, f.Finish)
+	field_string := fmt.Sprintf("Size: %vx%v\nStart: %v\nFinish: %v\n", f.Width, f.Length, f.Start, f.Finish)
+	if len(f.Checkpoints) > 0 {
+		field_string += fmt.Sprintf("Checkpoints: %v\n", f.Checkpoints)
+	}
+	field_string += "\n"
 
 	for i := len(f.labyrinth) - 1; i >= 0; i-- {
-		field_string += cellsArrayToString(f.labyrinth[i], " ") + "\n"
+		row := f.labyrinth[i]
+		if len(f.overlay) > 0 {
+			row = append([]cell{}, row...)
+			for j := range row {
+				if overlay_cell, ok := f.overlay[Coordinates{X: j, Y: i}]; ok && row[j].isPlainPassage() {
+					row[j] = overlay_cell
+				}
+			}
+		}
+		field_string += cellsArrayToString(row, " ") + "\n"
 	}
 
 	return field_string[:len(field_string)-1]
@@ -143,9 +229,12 @@ func (Field) Error(s string) error {
 	return fmt.Errorf("labyrinth error: %v", s)
 }
 
+// Amount of cells of every type
+type CellCounts map[cell]uint
+
 // Count all cell types in the labyrinth
-func (f *Field) CountCells() map[cell]uint {
-	counter := make(map[cell]uint)
+func (f *Field) CountCells() CellCounts {
+	counter := make(CellCounts)
 
 	for _, row := range f.labyrinth {
 		for _, cell := range row {
@@ -176,3 +265,59 @@ func (f *Field) FillEmptyCellsWithWalls() {
 		}
 	}
 }
+
+// Create a copy of the field that does not share cells with the original, configuration is shared
+func (f *Field) Copy() Field {
+	field_copy := *f
+	field_copy.labyrinth = make([][]cell, len(f.labyrinth))
+	for i, row := range f.labyrinth {
+		field_copy.labyrinth[i] = append([]cell{}, row...)
+	}
+	if f.mask != nil {
+		field_copy.mask = make(Mask, len(f.mask))
+		for i, row := range f.mask {
+			field_copy.mask[i] = append([]bool{}, row...)
+		}
+	}
+	field_copy.Checkpoints = append([]Coordinates(nil), f.Checkpoints...)
+	field_copy.overlay = make(map[Coordinates]cell, len(f.overlay))
+	for coords, overlay_cell := range f.overlay {
+		field_copy.overlay[coords] = overlay_cell
+	}
+
+	return field_copy
+}
+
+// Draw the route on the overlay as Path cells, the labyrinth itself stays unchanged
+// Cells other than plain passages keep showing what they are
+func (f *Field) DrawRoute(r Route) {
+	if f.overlay == nil {
+		f.overlay = make(map[Coordinates]cell)
+	}
+	it := r.GetIterator()
+	for coords, is_end := it(); !is_end; coords, is_end = it() {
+		if coords.IsValid(f.Width-1, f.Length-1) {
+			f.overlay[coords] = Path
+		}
+	}
+}
+
+// Remove everything drawn on the overlay
+func (f *Field) ClearOverlay() {
+	f.overlay = nil
+}
+
+// Create a copy of the field with the route written into its plain passages as Path
+// Start, finish, checkpoints, keys, doors and terrain on the route are kept, the field itself stays unchanged
+func (f *Field) WithRoute(r Route) Field {
+	field_copy := f.Copy()
+	field_copy.ClearOverlay()
+	it := r.GetIterator()
+	for coords, is_end := it(); !is_end; coords, is_end = it() {
+		if cell, err := field_copy.At(coords); err == nil && cell.isPlainPassage() {
+			field_copy.Set(Path, coords)
+		}
+	}
+
+	return field_copy
+}
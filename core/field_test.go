@@ -0,0 +1,76 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// Create a field of the chosen size without configuration, start in the bottom left corner,
+// finish in the top right one and walls in the listed cells
+func newTestField(width, length uint, walls ...Coordinates) *Field {
+	var f Field
+	f.SetSize(width, length)
+	f.SetStartAndFinish(Coordinates{X: 0, Y: 0}, Coordinates{X: int(width) - 1, Y: int(length) - 1})
+	for _, wall := range walls {
+		f.Set(Wall, wall)
+	}
+
+	return &f
+}
+
+// Setting start and finish keeps the cells of the previous ones, moving them empties the cells
+func TestSetAndMoveStartAndFinish(t *testing.T) {
+	f := newTestField(5, 5)
+	first_start, first_finish := f.Start, f.Finish
+
+	f.SetStartAndFinish(Coordinates{X: 1, Y: 0}, Coordinates{X: 3, Y: 4})
+	if cell, _ := f.At(first_start); cell != Start {
+		t.Errorf("SetStartAndFinish changed the previous start to %v", cell)
+	}
+	if cell, _ := f.At(first_finish); cell != Finish {
+		t.Errorf("SetStartAndFinish changed the previous finish to %v", cell)
+	}
+
+	f.MoveStartAndFinish(Coordinates{X: 2, Y: 0}, Coordinates{X: 2, Y: 4})
+	for _, c := range []Coordinates{{X: 1, Y: 0}, {X: 3, Y: 4}} {
+		if cell, _ := f.At(c); cell != Empty {
+			t.Errorf("MoveStartAndFinish left %v at %v", cell, c)
+		}
+	}
+	if f.Start != (Coordinates{X: 2, Y: 0}) || f.Finish != (Coordinates{X: 2, Y: 4}) {
+		t.Errorf("MoveStartAndFinish placed start at %v and finish at %v", f.Start, f.Finish)
+	}
+	if cell, _ := f.At(f.Start); cell != Start {
+		t.Errorf("start cell is %v", cell)
+	}
+}
+
+// Drawn route covers only plain passages, keys, doors and terrain on it stay, and the field it is written into a copy of stays the same
+func TestRouteKeepsSpecialCells(t *testing.T) {
+	f := newTestField(6, 1)
+	f.Set(RedKey, Coordinates{X: 1, Y: 0})
+	f.Set(Mud, Coordinates{X: 3, Y: 0})
+	f.Set(RedDoor, Coordinates{X: 4, Y: 0})
+	var route Route
+	route.Init(f.Start)
+	for x := 1; x < 6; x++ {
+		route.Add(Coordinates{X: x, Y: 0})
+	}
+	expected := []cell{Start, RedKey, Path, Mud, RedDoor, Finish}
+	before := f.String()
+
+	with_route := f.WithRoute(route)
+	for x, expected_cell := range expected {
+		if cell, _ := with_route.At(Coordinates{X: x, Y: 0}); cell != expected_cell {
+			t.Errorf("field with the route has %v at x=%v, expected %v", cell, x, expected_cell)
+		}
+	}
+	if after := f.String(); after != before {
+		t.Errorf("field changed from\n%v\nto\n%v", before, after)
+	}
+
+	f.DrawRoute(route)
+	if drawn, expected_row := f.String(), cellsArrayToString(expected, " "); !strings.HasSuffix(drawn, expected_row) {
+		t.Errorf("drawn route shows\n%v\nexpected\n%v", drawn, expected_row)
+	}
+}
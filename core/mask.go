@@ -0,0 +1,339 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+)
+
+// Shape of the usable area of a labyrinth, masked out cells are permanent walls
+// Rows go along the Y axis and columns along the X axis, true means the cell is usable
+type Mask [][]bool
+
+// Create a mask where every cell is usable
+func NewMask(width, length uint) Mask {
+	m := make(Mask, length)
+	for i := range m {
+		m[i] = make([]bool, width)
+		for j := range m[i] {
+			m[i][j] = true
+		}
+	}
+
+	return m
+}
+
+// Formatted error for usage in Mask
+func (Mask) Error(s string) error {
+	return fmt.Errorf("mask error: %v", s)
+}
+
+// Count usable cells of the mask
+func (m Mask) UsableSize() uint {
+	counter := uint(0)
+	for _, row := range m {
+		for _, usable := range row {
+			if usable {
+				counter++
+			}
+		}
+	}
+
+	return counter
+}
+
+// Create a mask with an ellipse touching every side of the field
+func CircleMask(width, length uint) Mask {
+	m := make(Mask, length)
+	radius_x, radius_y := float64(width)/2, float64(length)/2
+	for y := range m {
+		m[y] = make([]bool, width)
+		for x := range m[y] {
+			dx, dy := (float64(x)+0.5-radius_x)/radius_x, (float64(y)+0.5-radius_y)/radius_y
+			m[y][x] = dx*dx+dy*dy <= 1
+		}
+	}
+
+	return m
+}
+
+// Create a mask with a triangle standing on the bottom side of the field with its top in the middle of the top side
+func TriangleMask(width, length uint) Mask {
+	m := make(Mask, length)
+	for y := range m {
+		m[y] = make([]bool, width)
+		// rows with higher Y are shown on top, so the triangle narrows towards them
+		half_width := float64(int(length)-y) / float64(length) * float64(width) / 2
+		for x := range m[y] {
+			m[y][x] = math.Abs(float64(x)+0.5-float64(width)/2) <= half_width
+		}
+	}
+
+	return m
+}
+
+// Create a mask of the chosen size from a picture, where rows of the picture go from top to bottom
+// The picture is stretched to the size of the mask
+func maskFromPicture(picture [][]bool, width, length uint) Mask {
+	picture_width := 0
+	for _, row := range picture {
+		if len(row) > picture_width {
+			picture_width = len(row)
+		}
+	}
+
+	m := make(Mask, length)
+	for y := range m {
+		m[y] = make([]bool, width)
+		if len(picture) == 0 || picture_width == 0 {
+			continue
+		}
+		row := picture[len(picture)-1-y*len(picture)/int(length)]
+		for x := range m[y] {
+			if picture_x := x * picture_width / int(width); picture_x < len(row) {
+				m[y][x] = row[picture_x]
+			}
+		}
+	}
+
+	return m
+}
+
+// Load the mask from a PNG picture, where dark opaque pixels are usable, or from a text file,
+// where spaces and dots are masked out and any other character is usable
+// The picture is stretched to the chosen size
+func LoadMaskFromFile(file_path string, width, length uint) (Mask, error) {
+	if err := checkFilePath(file_path, false); err != nil {
+		return nil, err
+	}
+
+	var picture [][]bool
+	if strings.HasSuffix(strings.ToLower(file_path), ".png") {
+		file, err := os.Open(file_path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		img, err := png.Decode(file)
+		if err != nil {
+			return nil, err
+		}
+		picture = pictureFromImage(img)
+	} else {
+		data, err := ioutil.ReadFile(file_path)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\r\n"), "\n") {
+			line = strings.TrimRight(line, "\r")
+			row := make([]bool, 0, len(line))
+			for _, symbol := range line {
+				row = append(row, symbol != ' ' && symbol != '.')
+			}
+			picture = append(picture, row)
+		}
+	}
+
+	return maskFromPicture(picture, width, length), nil
+}
+
+// Turn dark opaque pixels of the image into usable cells
+func pictureFromImage(img image.Image) [][]bool {
+	bounds := img.Bounds()
+	picture := make([][]bool, bounds.Dy())
+	for y := range picture {
+		picture[y] = make([]bool, bounds.Dx())
+		for x := range picture[y] {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			picture[y][x] = a > 0x7fff && (r+g+b)/3 < 0x7fff
+		}
+	}
+
+	return picture
+}
+
+// Glyphs of the font used for text masks, 3 columns and 5 rows each
+var maskFont = map[rune][5]string{
+	'A': {"###", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {"###", "#..", "#..", "#..", "###"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {"###", "#..", "#.#", "#.#", "###"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", "###"},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {"###", "#.#", "#.#", "#.#", "###"},
+	'P': {"###", "#.#", "###", "#..", "#.."},
+	'Q': {"###", "#.#", "#.#", "###", "..#"},
+	'R': {"###", "#.#", "##.", "#.#", "#.#"},
+	'S': {"###", "#..", "###", "..#", "###"},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	' ': {"...", "...", "...", "...", "..."},
+}
+
+// Create a mask with the text written in the middle of the field, letters are scaled up as much as the field allows
+// Only latin letters, digits and spaces can be used
+func TextMask(text string, width, length uint) (Mask, error) {
+	text = strings.ToUpper(text)
+	if len(text) == 0 {
+		return nil, Mask{}.Error("Text of the mask cannot be empty")
+	}
+
+	// glyphs are separated by one empty column
+	picture := make([][]bool, 5)
+	for i, symbol := range text {
+		glyph, ok := maskFont[symbol]
+		if !ok {
+			return nil, Mask{}.Error(fmt.Sprintf("Symbol '%c' cannot be used in the text of the mask", symbol))
+		}
+		for row_idx, row := range glyph {
+			if i > 0 {
+				picture[row_idx] = append(picture[row_idx], false)
+			}
+			for _, pixel := range row {
+				picture[row_idx] = append(picture[row_idx], pixel == '#')
+			}
+		}
+	}
+
+	scale := uint(math.Min(float64(width)/float64(len(picture[0])), float64(length)/float64(len(picture))))
+	if scale == 0 {
+		return nil, Mask{}.Error(fmt.Sprintf("Text '%v' needs at least %vx%v cells", text, len(picture[0]), len(picture)))
+	}
+
+	// place the scaled up text in the middle of the field
+	m := make(Mask, length)
+	text_width, text_length := uint(len(picture[0]))*scale, uint(len(picture))*scale
+	offset_x, offset_y := int(width-text_width)/2, int(length-text_length)/2
+	for y := range m {
+		m[y] = make([]bool, width)
+		picture_y := y - offset_y
+		if picture_y < 0 || picture_y >= int(text_length) {
+			continue
+		}
+		row := picture[len(picture)-1-picture_y/int(scale)]
+		for x := range m[y] {
+			if picture_x := x - offset_x; picture_x >= 0 && picture_x < int(text_width) {
+				m[y][x] = row[picture_x/int(scale)]
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Create the mask described in the configuration for the field of the chosen size
+// Returns nil if the configuration does not describe any mask
+func (m mask) Build(width, length uint) (Mask, error) {
+	switch m.Shape {
+	case "":
+		return nil, nil
+	case "circle":
+		return CircleMask(width, length), nil
+	case "triangle":
+		return TriangleMask(width, length), nil
+	case "file":
+		return LoadMaskFromFile(m.File, width, length)
+	case "text":
+		return TextMask(m.Text, width, length)
+	default:
+		return nil, Mask{}.Error(fmt.Sprintf("Unknown mask shape '%v'", m.Shape))
+	}
+}
+
+// Use the mask for the labyrinth, masked out cells become walls and nothing can be placed on them
+// Start, finish and checkpoints cannot be masked out, nil mask makes every cell usable again
+func (f *Field) SetMask(m Mask) error {
+	if m == nil {
+		f.mask = nil
+		return nil
+	}
+	if uint(len(m)) != f.Length {
+		return f.Error(fmt.Sprintf("Mask should have %v rows, got %v", f.Length, len(m)))
+	}
+	for row_idx, row := range m {
+		if uint(len(row)) != f.Width {
+			return f.Error(fmt.Sprintf("Mask should have %v cells in every row, row #%v has %v", f.Width, row_idx, len(row)))
+		}
+	}
+	for _, c := range append([]Coordinates{f.Start, f.Finish}, f.Checkpoints...) {
+		if c.IsValid(f.Width-1, f.Length-1) && !m[c.Y][c.X] {
+			return f.Error(fmt.Sprintf("Cannot mask out start, finish or checkpoint at %v", c))
+		}
+	}
+
+	f.mask = make(Mask, len(m))
+	for i, row := range m {
+		f.mask[i] = append([]bool{}, row...)
+		for j, usable := range row {
+			if !usable {
+				f.labyrinth[i][j] = Wall
+			}
+		}
+	}
+
+	return nil
+}
+
+// Get a copy of the mask used for the labyrinth, nil if every cell is usable
+func (f *Field) Mask() Mask {
+	if f.mask == nil {
+		return nil
+	}
+
+	m := make(Mask, len(f.mask))
+	for i, row := range f.mask {
+		m[i] = append([]bool{}, row...)
+	}
+
+	return m
+}
+
+// Check that the cell at the chosen coordinates is within the field's bounds and is not masked out
+func (f *Field) IsUsable(c Coordinates) bool {
+	return c.IsValid(f.Width-1, f.Length-1) && (f.mask == nil || f.mask[c.Y][c.X])
+}
+
+// Count the amount of cells in labyrinth that are not masked out
+func (f *Field) UsableSize() uint {
+	if f.mask == nil {
+		return f.Size()
+	}
+
+	return f.mask.UsableSize()
+}
+
+// Get cell type at given coordinates, masked out cells are treated as if they were out of the field's bounds
+func (f *Field) UsableAt(c Coordinates) (cell, error) {
+	if c.IsValid(f.Width-1, f.Length-1) && !f.IsUsable(c) {
+		return Wall, f.Error(fmt.Sprintf("Cell %v is masked out", c))
+	}
+
+	return f.At(c)
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Masks described in configuration have the size of the field, shapes leave the top corners out
+func TestBuildMask(t *testing.T) {
+	for _, m := range []mask{{Shape: "circle"}, {Shape: "triangle"}, {Shape: "text", Text: "Hi 2"}} {
+		built, err := m.Build(30, 20)
+		if err != nil {
+			t.Fatalf("%v: %v", m.Shape, err)
+		}
+		if len(built) != 20 || len(built[0]) != 30 {
+			t.Errorf("%v: mask has %v rows of %v cells, expected 20 rows of 30", m.Shape, len(built), len(built[0]))
+		}
+		if usable := built.UsableSize(); usable == 0 || usable == 600 {
+			t.Errorf("%v: mask has %v usable cells out of 600", m.Shape, usable)
+		}
+		if built[19][0] || built[19][29] {
+			t.Errorf("%v: top corners of the mask are usable", m.Shape)
+		}
+	}
+
+	if built, err := (mask{}).Build(30, 20); built != nil || err != nil {
+		t.Errorf("empty shape built mask %v with error %v", built, err)
+	}
+	for _, m := range []mask{{Shape: "hexagon"}, {Shape: "text", Text: "ä"}, {Shape: "text", Text: "TOO LONG"}} {
+		if _, err := m.Build(10, 4); err == nil {
+			t.Errorf("mask %+v was built", m)
+		}
+	}
+}
+
+// Text files are stretched to the size of the mask, their first line becomes the top row
+func TestLoadMaskFromTextFile(t *testing.T) {
+	file_path := filepath.Join(t.TempDir(), "mask.txt")
+	if err := os.WriteFile(file_path, []byte("#.\n.#\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := LoadMaskFromFile(file_path, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Mask{{false, false, true, true}, {true, true, false, false}}); !reflect.DeepEqual(m, expected) {
+		t.Errorf("loaded mask %v, expected %v", m, expected)
+	}
+}
+
+// Masked out cells turn into walls that cannot be changed until the mask is removed
+func TestSetMask(t *testing.T) {
+	f := newTestField(3, 3)
+	m := NewMask(3, 3)
+	m[1][1] = false
+	if err := f.SetMask(m); err != nil {
+		t.Fatal(err)
+	}
+
+	center := Coordinates{X: 1, Y: 1}
+	f.Set(Empty, center)
+	if cell, _ := f.At(center); cell != Wall || f.IsUsable(center) || f.UsableSize() != 8 {
+		t.Errorf("masked out cell is %v, usable %v, usable size %v", cell, f.IsUsable(center), f.UsableSize())
+	}
+	if _, err := f.UsableAt(center); err == nil {
+		t.Error("masked out cell is usable")
+	}
+	f.MakeEmpty(true)
+	if cell, _ := f.At(center); cell != Wall {
+		t.Errorf("masked out cell became %v after emptying the field", cell)
+	}
+
+	if err := f.SetMask(nil); err != nil {
+		t.Fatal(err)
+	}
+	f.Set(Empty, center)
+	if cell, _ := f.At(center); cell != Empty || f.Mask() != nil {
+		t.Errorf("cell is %v after removing the mask", cell)
+	}
+}
+
+// Masks of the wrong size or masking out start, finish or checkpoints are not set
+func TestSetInvalidMask(t *testing.T) {
+	f := newTestField(3, 3)
+	if err := f.SetCheckpoints(Coordinates{X: 1, Y: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, masked_out := range []Coordinates{f.Start, f.Finish, f.Checkpoints[0]} {
+		m := NewMask(3, 3)
+		m[masked_out.Y][masked_out.X] = false
+		if err := f.SetMask(m); err == nil {
+			t.Errorf("mask without %v was set", masked_out)
+		}
+	}
+	for _, m := range []Mask{NewMask(3, 2), NewMask(2, 3)} {
+		if err := f.SetMask(m); err == nil {
+			t.Errorf("mask of %v rows of %v cells was set on 3x3 field", len(m), len(m[0]))
+		}
+	}
+	if f.Mask() != nil {
+		t.Errorf("invalid mask was left on the field %v", f.Mask())
+	}
+}
@@ -102,3 +102,22 @@ func (r Route) CopyUntil(n uint) (Route, error) {
 
 	return new_route, nil
 }
+
+// Start a new route from the step at the position of the route, counting from 0
+// Steps before it are shared with the route instead of being copied, so the new route only counts its own steps
+// but going back from its end still leads through them
+func (r Route) Branch(position uint) (Route, error) {
+	if r.Length == 0 {
+		return Route{}, r.Error("Cannot branch off an uninitialized route")
+	} else if position >= r.Length {
+		return Route{}, r.Error(fmt.Sprintf("position=%v is out of route length=%v\n", position, r.Length))
+	}
+
+	step := r.Start
+	for counter := uint(0); counter < position; counter++ {
+		step = step.Next
+	}
+	new_step := routeStep{Coords: step.Coords, Next: nil, Prev: step.Prev}
+
+	return Route{Start: &new_step, End: &new_step, Length: 1}, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	builder "github.com/Via-R/labyrinth-go/builder"
 	core "github.com/Via-R/labyrinth-go/core"
+	solver "github.com/Via-R/labyrinth-go/solver"
+	"log"
 )
 
 const build_new_labyrinth = false
@@ -12,10 +14,13 @@ const build_new_labyrinth = false
 func builderDemo(l *core.Field) {
 	l.SetSize(16, 16)
 	l.SetStartAndFinish(core.Coordinates{X: 0, Y: 4}, core.Coordinates{X: 15, Y: 3})
-	if err := builder.GenerateLabyrinth(l); err != nil {
+	report, err := builder.GenerateLabyrinth(l, builder.WithLogger(log.Default()))
+	if err != nil {
 		panic(err)
 	}
 	fmt.Println(l)
+	fmt.Printf("Seed: %v\n", l.Seed)
+	fmt.Printf("Attempts: %v, routes: %v, empty area: %.1f%%, took %v\n", report.Attempts, report.Routes, report.EmptyArea, report.Duration)
 	if err := l.SaveLabyrinthToFile("examples/16x16.json"); err != nil {
 		fmt.Println(err)
 	}
@@ -27,6 +32,12 @@ func solverDemo(l *core.Field) {
 		panic(err)
 	}
 	fmt.Println(l)
+	route, err := solver.Solve(l)
+	if err != nil {
+		panic(err)
+	}
+	l.DrawRoute(route)
+	fmt.Printf("\nSolution (%v steps):\n%v\n", route.Length-1, l)
 }
 
 func main() {
@@ -0,0 +1,177 @@
+package solver
+
+import (
+	"container/heap"
+	"context"
+	"math"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Estimation of the remaining distance between two cells used to guide A* search
+type Heuristic interface {
+	Estimate(from, to core.Coordinates) float64
+}
+
+// Straight line distance between cells
+type EuclideanHeuristic struct{}
+
+// Sum of horizontal and vertical distances between cells
+type ManhattanHeuristic struct{}
+
+// Biggest of horizontal and vertical distances between cells
+type ChebyshevHeuristic struct{}
+
+// No estimation at all, which turns A* into Dijkstra's algorithm
+type ZeroHeuristic struct{}
+
+// Estimate the remaining distance as straight line distance
+func (EuclideanHeuristic) Estimate(from, to core.Coordinates) float64 {
+	return from.Distance(to)
+}
+
+// Estimate the remaining distance as Manhattan distance
+func (ManhattanHeuristic) Estimate(from, to core.Coordinates) float64 {
+	return math.Abs(float64(to.X-from.X)) + math.Abs(float64(to.Y-from.Y))
+}
+
+// Estimate the remaining distance as Chebyshev distance
+func (ChebyshevHeuristic) Estimate(from, to core.Coordinates) float64 {
+	return math.Max(math.Abs(float64(to.X-from.X)), math.Abs(float64(to.Y-from.Y)))
+}
+
+// Estimate the remaining distance as zero
+func (ZeroHeuristic) Estimate(from, to core.Coordinates) float64 {
+	return 0
+}
+
+// Cell waiting in the A* open set
+type openCell struct {
+	idx      int
+	cost     int // cost of the route from start to this cell
+	priority float64
+}
+
+// Priority queue of cells ordered by estimated total route cost
+type openSet []openCell
+
+// Implementation of heap.Interface for the open set
+func (s openSet) Len() int { return len(s) }
+
+func (s openSet) Less(i, j int) bool {
+	if s[i].priority == s[j].priority {
+		// prefer cells further from the start, they are more likely to be closer to the finish
+		return s[i].cost > s[j].cost
+	}
+	return s[i].priority < s[j].priority
+}
+
+func (s openSet) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *openSet) Push(x any) { *s = append(*s, x.(openCell)) }
+
+func (s *openSet) Pop() any {
+	old := *s
+	last := old[len(old)-1]
+	*s = old[:len(old)-1]
+	return last
+}
+
+// A* search, finds the cheapest route as long as the heuristic never overestimates the distance
+// Every cell costs at least 1 to walk through, so none of the provided heuristics overestimate it
+type AStar struct {
+	Heuristic Heuristic // ZeroHeuristic is used if it is not set
+}
+
+// Find the cheapest route from start to finish with A* search guided by the heuristic
+// Every expanded cell is recorded in the result
+func (a AStar) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+	h := a.Heuristic
+	if h == nil {
+		h = ZeroHeuristic{}
+	}
+
+	t := newTrace(ctx, observe)
+	parents, costs := newCellsArray(f, -1), newCellsArray(f, -1)
+	closed := make([]bool, f.Size())
+	start_idx := index(f, f.Start)
+	parents[start_idx], costs[start_idx] = start_idx, 0
+	open := &openSet{{idx: start_idx, cost: 0, priority: h.Estimate(f.Start, f.Finish)}}
+
+	for open.Len() > 0 {
+		curr := heap.Pop(open).(openCell)
+		if closed[curr.idx] {
+			continue
+		}
+		closed[curr.idx] = true
+
+		coords := coordinatesAt(f, curr.idx)
+		if err := t.emit(Visit, coords); err != nil {
+			return Result{Visited: t.visited}, err
+		}
+		if coords == f.Finish {
+			err := t.emit(Found, coords)
+			return Result{Route: routeFromParents(f, parents, f.Start, f.Finish), Visited: t.visited}, err
+		}
+
+		for _, neighbor := range walkableNeighbors(f, coords) {
+			neighbor_idx := index(f, neighbor)
+			neighbor_cell, _ := f.At(neighbor)
+			cost := curr.cost + int(neighbor_cell.Cost())
+			if closed[neighbor_idx] || costs[neighbor_idx] != -1 && costs[neighbor_idx] <= cost {
+				continue
+			}
+			parents[neighbor_idx], costs[neighbor_idx] = curr.idx, cost
+			heap.Push(open, openCell{idx: neighbor_idx, cost: cost, priority: float64(cost) + h.Estimate(neighbor, f.Finish)})
+			if err := t.emit(Enqueue, neighbor); err != nil {
+				return Result{Visited: t.visited}, err
+			}
+		}
+	}
+
+	return Result{Visited: t.visited}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+}
+
+// Find the cheapest route from start to finish with A* search guided by the heuristic
+// Returns the route and the amount of cells expanded during the search
+func SolveAStar(f *core.Field, h Heuristic) (core.Route, uint, error) {
+	result, err := AStar{Heuristic: h}.Solve(context.Background(), f, nil)
+
+	return result.Route, uint(len(result.Visited)), err
+}
+
+// Dijkstra's algorithm, finds the route with the lowest total cost of walking through its cells
+type Dijkstra struct{}
+
+// Find the cheapest route from start to finish with Dijkstra's algorithm
+func (Dijkstra) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	return AStar{Heuristic: ZeroHeuristic{}}.Solve(ctx, f, observe)
+}
+
+// Find the cheapest route from start to finish with Dijkstra's algorithm
+// Returns the route and its total cost
+func SolveDijkstra(f *core.Field) (core.Route, uint, error) {
+	result, err := Dijkstra{}.Solve(context.Background(), f, nil)
+	if err != nil {
+		return core.Route{}, 0, err
+	}
+
+	return result.Route, RouteCost(f, result.Route), nil
+}
+
+// Calculate the total cost of walking the route, the first step is free as the route starts on it
+func RouteCost(f *core.Field, r core.Route) uint {
+	total := uint(0)
+	it := r.GetIterator()
+	it()
+	for coords, is_end := it(); !is_end; coords, is_end = it() {
+		if cell, err := f.At(coords); err == nil {
+			total += cell.Cost()
+		}
+	}
+
+	return total
+}
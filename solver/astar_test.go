@@ -0,0 +1,69 @@
+package solver
+
+import (
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Dijkstra's algorithm goes around the water in the straight way, which breadth-first search walks through
+func TestDijkstraAvoidsCostlyTerrain(t *testing.T) {
+	f := newTestField(t, 5, 3, core.Coordinates{X: 0, Y: 1}, core.Coordinates{X: 4, Y: 1})
+	for x := 1; x < 4; x++ {
+		f.Set(core.Water, core.Coordinates{X: x, Y: 1})
+	}
+
+	shortest, err := Solve(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shortest.Length != 5 || RouteCost(f, shortest) != 16 {
+		t.Errorf("breadth-first route %v has %v cells and costs %v, expected 5 cells costing 16", shortest, shortest.Length, RouteCost(f, shortest))
+	}
+
+	cheapest, cost, err := SolveDijkstra(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoute(t, "dijkstra", f, cheapest)
+	if cheapest.Length != 7 || cost != 6 {
+		t.Errorf("cheapest route %v has %v cells and costs %v, expected 7 cells costing 6", cheapest, cheapest.Length, cost)
+	}
+	for _, step := range routeSteps(cheapest) {
+		if cell, _ := f.At(step); cell.IsTerrain() {
+			t.Errorf("cheapest route %v goes through %v at %v", cheapest, cell, step)
+		}
+	}
+}
+
+// Informed heuristics expand fewer cells than the zero heuristic and still find routes as cheap as Dijkstra's algorithm
+func TestHeuristicsExpandFewerCells(t *testing.T) {
+	open := newTestField(t, 30, 20, core.Coordinates{X: 3, Y: 17}, core.Coordinates{X: 26, Y: 2})
+	fields := []*core.Field{open, newGeneratedField(t, "dungeon", 1)}
+
+	heuristics := map[string]Heuristic{"euclidean": EuclideanHeuristic{}, "manhattan": ManhattanHeuristic{}, "chebyshev": ChebyshevHeuristic{}}
+	for _, f := range fields {
+		_, cheapest_cost, err := SolveDijkstra(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, zero_expanded, err := SolveAStar(f, ZeroHeuristic{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, h := range heuristics {
+			route, expanded, err := SolveAStar(f, h)
+			if err != nil {
+				t.Errorf("%v: %v", name, err)
+				continue
+			}
+			checkRoute(t, name, f, route)
+			if cost := RouteCost(f, route); cost != cheapest_cost {
+				t.Errorf("%v: route costs %v, the cheapest one costs %v\n%v", name, cost, cheapest_cost, f)
+			}
+			if expanded >= zero_expanded {
+				t.Errorf("%v: expanded %v cells, the zero heuristic expanded %v\n%v", name, expanded, zero_expanded, f)
+			}
+		}
+	}
+}
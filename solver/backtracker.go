@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"context"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Depth-first search that goes as deep as possible and backtracks from dead ends
+// The stack is kept explicitly, so huge labyrinths do not overflow the call stack
+type Backtracker struct{}
+
+// Step on the route being explored
+type backtrackerStep struct {
+	coords    core.Coordinates
+	direction int // next direction to try
+}
+
+// Explore the labyrinth depth-first until the finish is reached
+func (Backtracker) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	t := newTrace(ctx, observe)
+	visited := make([]bool, f.Size())
+	visited[index(f, f.Start)] = true
+	stack := []backtrackerStep{{coords: f.Start}}
+	if err := t.emit(Visit, f.Start); err != nil {
+		return Result{Visited: t.visited}, err
+	}
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.coords == f.Finish {
+			steps := make([]core.Coordinates, len(stack))
+			for i, step := range stack {
+				steps[i] = step.coords
+			}
+			err := t.emit(Found, top.coords)
+			return Result{Route: routeFromSteps(steps), Visited: t.visited}, err
+		}
+
+		if top.direction == len(builder.NeumannShifts) {
+			if err := t.emit(Backtrack, top.coords); err != nil {
+				return Result{Visited: t.visited}, err
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next, ok := neighborAt(f, top.coords, top.direction)
+		top.direction++
+		if ok && !visited[index(f, next)] {
+			visited[index(f, next)] = true
+			stack = append(stack, backtrackerStep{coords: next})
+			if err := t.emit(Visit, next); err != nil {
+				return Result{Visited: t.visited}, err
+			}
+		}
+	}
+
+	return Result{Visited: t.visited}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+}
@@ -0,0 +1,203 @@
+package solver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Breadth-first search from start and finish at the same time, the route is joined where the searches meet
+// Every step counts as one, terrain costs are ignored
+type Bidirectional struct {
+	Concurrent bool // expand both frontiers in separate goroutines
+}
+
+// One of the two searches, going from start or from finish
+// Cells are kept shifted by one, so that the zeroed arrays mean that nothing was reached yet and the search
+// does not have to go over the whole field before it starts
+type searchSide struct {
+	distances      []int32 // distance to every cell plus one, accessed atomically, as the other side reads them concurrently
+	parents        []int   // cell index plus one of the parent of every cell, pointing back towards the origin of the side
+	frontier       []core.Coordinates
+	meeting        int   // cell where this side reached the other one on the shortest route found, -1 if they did not meet
+	meeting_length int32 // length of the route through the meeting cell in steps
+}
+
+// Create a search going from the chosen origin
+func newSearchSide(f *core.Field, origin core.Coordinates) *searchSide {
+	side := &searchSide{distances: make([]int32, f.Size()), parents: make([]int, f.Size()), frontier: []core.Coordinates{origin}, meeting: -1}
+	side.reach(index(f, origin), 0, index(f, origin))
+
+	return side
+}
+
+// Distance from the origin of the side to the cell, -1 if the cell was not reached yet
+func (s *searchSide) distance(idx int) int32 {
+	return atomic.LoadInt32(&s.distances[idx]) - 1
+}
+
+// Parent of the reached cell, the origin is its own parent
+func (s *searchSide) parent(idx int) int {
+	return s.parents[idx] - 1
+}
+
+// Mark the cell as reached from the parent
+func (s *searchSide) reach(idx int, distance int32, parent int) {
+	s.parents[idx] = parent + 1
+	atomic.StoreInt32(&s.distances[idx], distance+1)
+}
+
+// Remember the cell reached by both sides if the route through it is the shortest one found by this side
+func (s *searchSide) meet(idx int, length int32) {
+	if s.meeting == -1 || length < s.meeting_length {
+		s.meeting, s.meeting_length = idx, length
+	}
+}
+
+// Expand the whole frontier by one level
+// Cells reached by the other side as well are remembered as meeting cells, returns whether there were any
+func (s *searchSide) expand(f *core.Field, other *searchSide, t *trace) (bool, error) {
+	met := false
+	next_frontier := make([]core.Coordinates, 0, len(s.frontier))
+	for _, coords := range s.frontier {
+		if err := t.emit(Visit, coords); err != nil {
+			return false, err
+		}
+		coords_idx := index(f, coords)
+		for direction := range builder.NeumannShifts {
+			neighbor, ok := neighborAt(f, coords, direction)
+			if !ok {
+				continue
+			}
+			neighbor_idx := index(f, neighbor)
+			if s.distance(neighbor_idx) != -1 {
+				continue
+			}
+			distance := s.distance(coords_idx) + 1
+			s.reach(neighbor_idx, distance, coords_idx)
+			next_frontier = append(next_frontier, neighbor)
+			if err := t.emit(Enqueue, neighbor); err != nil {
+				return false, err
+			}
+			if other_distance := other.distance(neighbor_idx); other_distance != -1 {
+				s.meet(neighbor_idx, distance+other_distance)
+				met = true
+			}
+		}
+	}
+	s.frontier = next_frontier
+
+	return met, nil
+}
+
+// Join both searches into the shortest route through the cells where they met
+// Both searches have to consist of fully expanded levels, otherwise the route might not be the shortest
+func joinSearches(f *core.Field, from_start, from_finish *searchSide) (core.Route, bool) {
+	meeting := from_start.meeting
+	if from_finish.meeting != -1 && (meeting == -1 || from_finish.meeting_length < from_start.meeting_length) {
+		meeting = from_finish.meeting
+	}
+	if meeting == -1 {
+		return core.Route{}, false
+	}
+
+	steps := []core.Coordinates{}
+	for curr := meeting; ; curr = from_start.parent(curr) {
+		steps = append(steps, coordinatesAt(f, curr))
+		if from_start.parent(curr) == curr {
+			break
+		}
+	}
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	for curr := meeting; from_finish.parent(curr) != curr; {
+		curr = from_finish.parent(curr)
+		steps = append(steps, coordinatesAt(f, curr))
+	}
+
+	return routeFromSteps(steps), true
+}
+
+// Search from both ends until the searches meet and join them into the shortest route
+func (b Bidirectional) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	t := newTrace(ctx, observe)
+	from_start, from_finish := newSearchSide(f, f.Start), newSearchSide(f, f.Finish)
+	if f.Start == f.Finish {
+		from_start.meet(index(f, f.Start), 0)
+	} else {
+		var err error
+		if b.Concurrent {
+			err = expandConcurrently(f, from_start, from_finish, t)
+		} else {
+			err = expandAlternately(f, from_start, from_finish, t)
+		}
+		if err != nil {
+			return Result{Visited: t.visited}, err
+		}
+	}
+
+	route, ok := joinSearches(f, from_start, from_finish)
+	if !ok {
+		return Result{Visited: t.visited}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+	}
+	err := t.emit(Found, f.Finish)
+
+	return Result{Route: route, Visited: t.visited}, err
+}
+
+// Expand the smaller frontier level by level until the searches meet or one of them runs out of cells
+func expandAlternately(f *core.Field, from_start, from_finish *searchSide, t *trace) error {
+	for len(from_start.frontier) > 0 && len(from_finish.frontier) > 0 {
+		side, other := from_start, from_finish
+		if len(from_finish.frontier) < len(from_start.frontier) {
+			side, other = from_finish, from_start
+		}
+		if met, err := side.expand(f, other, t); err != nil || met {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Expand both searches in their own goroutines until they meet or one of them runs out of cells
+// Each goroutine finishes its current level before stopping, so both searches consist of complete levels
+func expandConcurrently(f *core.Field, from_start, from_finish *searchSide, t *trace) error {
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, sides := range [2][2]*searchSide{{from_start, from_finish}, {from_finish, from_start}} {
+		wg.Add(1)
+		go func(i int, side, other *searchSide) {
+			defer wg.Done()
+			for !stop.Load() && len(side.frontier) > 0 {
+				met, err := side.expand(f, other, t)
+				if err != nil {
+					errs[i] = err
+					stop.Store(true)
+				} else if met {
+					stop.Store(true)
+				}
+			}
+			// running out of cells means that the searches can never meet
+			stop.Store(true)
+		}(i, sides[0], sides[1])
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
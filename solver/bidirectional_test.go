@@ -0,0 +1,66 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Both ways of expanding the searches find a route as short as the one of breadth-first search
+func TestBidirectionalMatchesBreadthFirst(t *testing.T) {
+	fields := []*core.Field{newTestField(t, 30, 20, core.Coordinates{X: 3, Y: 17}, core.Coordinates{X: 26, Y: 2})}
+	for seed := int64(0); seed < 3; seed++ {
+		fields = append(fields, newGeneratedField(t, "route-growing", seed), newGeneratedField(t, "dungeon", seed))
+	}
+
+	for _, f := range fields {
+		shortest, err := Solve(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, concurrent := range []bool{false, true} {
+			result, err := Bidirectional{Concurrent: concurrent}.Solve(context.Background(), f, nil)
+			if err != nil {
+				t.Errorf("concurrent=%v: %v\n%v", concurrent, err, f)
+				continue
+			}
+			checkRoute(t, "bidirectional", f, result.Route)
+			if result.Route.Length != shortest.Length {
+				t.Errorf("concurrent=%v: route has %v cells, breadth-first search found %v\n%v", concurrent, result.Route.Length, shortest.Length, f)
+			}
+		}
+	}
+}
+
+// Searches that run out of cells before meeting report that the labyrinth cannot be solved
+func TestBidirectionalUnsolvable(t *testing.T) {
+	f := newTestField(t, 5, 5, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 4, Y: 4}, column(2, 5)...)
+
+	for _, concurrent := range []bool{false, true} {
+		var unsolvable UnsolvableError
+		if _, err := (Bidirectional{Concurrent: concurrent}).Solve(context.Background(), f, nil); !errors.As(err, &unsolvable) {
+			t.Errorf("concurrent=%v: expected the unsolvable error, got %v", concurrent, err)
+		}
+	}
+}
+
+// Compare with breadth-first search on a large open field, where the searches meet long before covering it
+func BenchmarkBidirectional(b *testing.B) {
+	f := newTestField(b, 2048, 2048, core.Coordinates{X: 924, Y: 924}, core.Coordinates{X: 1124, Y: 1124})
+	solvers := map[string]Solver{
+		"breadth-first": BreadthFirst{},
+		"bidirectional": Bidirectional{},
+		"concurrent":    Bidirectional{Concurrent: true},
+	}
+	for _, name := range []string{"breadth-first", "bidirectional", "concurrent"} {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := solvers[name].Solve(context.Background(), f, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
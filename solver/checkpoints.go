@@ -0,0 +1,140 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Amount of checkpoints above which the best order of visiting them takes too long to find
+const maxUnorderedCheckpoints = 16
+
+// Breadth-first search of the shortest route from start to finish that goes through every checkpoint of the field
+// Checkpoints are visited in the order they are listed in the field if Ordered is set, otherwise the best order is found
+type Checkpoints struct {
+	Ordered bool
+}
+
+// Find the order of visiting the checkpoints which gives the shortest route
+// Distances hold the length of the shortest route between every pair of waypoints,
+// where the first waypoint is the start, the last one is the finish and the checkpoints are in between
+func bestCheckpointsOrder(distances [][]uint) []int {
+	checkpoints := len(distances) - 2
+	if checkpoints == 0 {
+		return []int{}
+	}
+
+	// shortest[visited][last] is the length of the route from start through the visited checkpoints ending at the last one
+	shortest, previous := make([][]uint, 1<<checkpoints), make([][]int, 1<<checkpoints)
+	for visited := range shortest {
+		shortest[visited], previous[visited] = make([]uint, checkpoints), make([]int, checkpoints)
+		for last := range shortest[visited] {
+			shortest[visited][last], previous[visited][last] = math.MaxUint, -1
+		}
+	}
+	for last := 0; last < checkpoints; last++ {
+		shortest[1<<last][last] = distances[0][last+1]
+	}
+
+	for visited := 1; visited < 1<<checkpoints; visited++ {
+		for last := 0; last < checkpoints; last++ {
+			if visited&(1<<last) == 0 || shortest[visited][last] == math.MaxUint {
+				continue
+			}
+			for next := 0; next < checkpoints; next++ {
+				if visited&(1<<next) != 0 {
+					continue
+				}
+				length := shortest[visited][last] + distances[last+1][next+1]
+				if length < shortest[visited|1<<next][next] {
+					shortest[visited|1<<next][next], previous[visited|1<<next][next] = length, last
+				}
+			}
+		}
+	}
+
+	all_visited, best_last := 1<<checkpoints-1, 0
+	for last := 1; last < checkpoints; last++ {
+		if shortest[all_visited][last]+distances[last+1][checkpoints+1] < shortest[all_visited][best_last]+distances[best_last+1][checkpoints+1] {
+			best_last = last
+		}
+	}
+
+	order := make([]int, checkpoints)
+	for i, visited, last := checkpoints-1, all_visited, best_last; i >= 0; i-- {
+		order[i] = last
+		visited, last = visited&^(1<<last), previous[visited][last]
+	}
+
+	return order
+}
+
+// Order the checkpoints of the field in the way they should be visited
+func (c Checkpoints) order(f *core.Field) ([]core.Coordinates, error) {
+	if c.Ordered && !f.CheckpointsOrderKnown() {
+		return nil, f.Error("Order of the checkpoints is unknown, as they were loaded row by row, they can only be solved unordered")
+	}
+	if c.Ordered || len(f.Checkpoints) < 2 {
+		return f.Checkpoints, nil
+	}
+	if len(f.Checkpoints) > maxUnorderedCheckpoints {
+		return nil, f.Error(fmt.Sprintf("Cannot find the best order of more than %v checkpoints, %v were given", maxUnorderedCheckpoints, len(f.Checkpoints)))
+	}
+
+	waypoints := append(append([]core.Coordinates{f.Start}, f.Checkpoints...), f.Finish)
+	distances := make([][]uint, len(waypoints))
+	for i, from := range waypoints {
+		distances[i] = make([]uint, len(waypoints))
+		for j, to := range waypoints {
+			if i == j {
+				continue
+			}
+			route, err := breadthFirstBetween(f, nil, from, to, nil)
+			if err != nil {
+				return nil, err
+			}
+			distances[i][j] = route.Length - 1
+		}
+	}
+
+	order := bestCheckpointsOrder(distances)
+	ordered := make([]core.Coordinates, len(order))
+	for i, checkpoint := range order {
+		ordered[i] = f.Checkpoints[checkpoint]
+	}
+
+	return ordered, nil
+}
+
+// Find the shortest route from start to finish which goes through every checkpoint
+// The route might go through the same cell several times, e.g. when coming back from a checkpoint in a dead end
+// Found is reported every time a checkpoint or the finish is reached
+func (c Checkpoints) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	checkpoints, err := c.order(f)
+	if err != nil {
+		return Result{}, err
+	}
+
+	t := newTrace(ctx, observe)
+	waypoints := append(append([]core.Coordinates{f.Start}, checkpoints...), f.Finish)
+	steps := []core.Coordinates{f.Start}
+	for i := 1; i < len(waypoints); i++ {
+		leg, err := breadthFirstBetween(f, t, waypoints[i-1], waypoints[i], nil)
+		if err != nil {
+			return Result{Visited: t.visited}, err
+		}
+		it := leg.GetIterator()
+		it()
+		for step, is_end := it(); !is_end; step, is_end = it() {
+			steps = append(steps, step)
+		}
+	}
+
+	return Result{Route: routeFromSteps(steps), Visited: t.visited}, nil
+}
@@ -0,0 +1,70 @@
+package solver
+
+import (
+	"context"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Find the first position of the coordinates among the steps, -1 if they are not there
+func stepIndex(steps []core.Coordinates, c core.Coordinates) int {
+	for i, step := range steps {
+		if step == c {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Ordered solving visits the checkpoints in the listed order, unordered solving finds a shorter way
+func TestCheckpointsOrder(t *testing.T) {
+	// visiting the right checkpoint first makes the route go along the corridor twice
+	f := newTestField(t, 7, 1, core.Coordinates{X: 3, Y: 0}, core.Coordinates{X: 6, Y: 0})
+	if err := f.SetCheckpoints(core.Coordinates{X: 5, Y: 0}, core.Coordinates{X: 0, Y: 0}); err != nil {
+		t.Fatal(err)
+	}
+	ordered, err := Checkpoints{Ordered: true}.Solve(context.Background(), f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := routeSteps(ordered.Route)
+	if first, second := stepIndex(steps, f.Checkpoints[0]), stepIndex(steps, f.Checkpoints[1]); first == -1 || second < first {
+		t.Errorf("ordered route %v does not visit %v before %v", ordered.Route, f.Checkpoints[0], f.Checkpoints[1])
+	}
+	if steps[0] != f.Start || steps[len(steps)-1] != f.Finish || ordered.Route.Length != 14 {
+		t.Errorf("ordered route %v has length %v, expected 14 cells from start to finish", ordered.Route, ordered.Route.Length)
+	}
+
+	unordered, err := Checkpoints{}.Solve(context.Background(), f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unordered.Route.Length != 10 {
+		t.Errorf("unordered route %v has length %v, expected 10 cells", unordered.Route, unordered.Route.Length)
+	}
+	for _, checkpoint := range f.Checkpoints {
+		if stepIndex(routeSteps(unordered.Route), checkpoint) == -1 {
+			t.Errorf("unordered route %v does not visit %v", unordered.Route, checkpoint)
+		}
+	}
+}
+
+// Checkpoints found row by row in loaded cells can only be solved unordered
+func TestCheckpointsUnknownOrder(t *testing.T) {
+	corridor := newTestField(t, 7, 1, core.Coordinates{X: 3, Y: 0}, core.Coordinates{X: 6, Y: 0})
+	if err := corridor.SetCheckpoints(core.Coordinates{X: 5, Y: 0}, core.Coordinates{X: 0, Y: 0}); err != nil {
+		t.Fatal(err)
+	}
+	var f core.Field
+	if err := f.LoadLabyrinth(corridor.GetLabyrinth()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Checkpoints{Ordered: true}).Solve(context.Background(), &f, nil); err == nil {
+		t.Errorf("checkpoints %v of unknown order were solved in order", f.Checkpoints)
+	}
+	if _, err := (Checkpoints{}).Solve(context.Background(), &f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"context"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Fill every dead end until only the routes between start and finish are left
+type DeadEndFilling struct{}
+
+// Fill dead ends one by one and find the route through what is left
+// Visited cells are the filled ones in the order of filling followed by the search through the remaining ones
+func (DeadEndFilling) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	t := newTrace(ctx, observe)
+	filled := make([]bool, f.Size())
+	open_neighbors := make([]int, f.Size())
+	dead_ends := make([]core.Coordinates, 0)
+	is_dead_end := func(coords core.Coordinates) bool {
+		return coords != f.Start && coords != f.Finish && open_neighbors[index(f, coords)] <= 1
+	}
+
+	for y := 0; y < int(f.Length); y++ {
+		for x := 0; x < int(f.Width); x++ {
+			coords := core.Coordinates{X: x, Y: y}
+			if cell, _ := f.At(coords); !cell.IsWalkable() {
+				continue
+			}
+			open_neighbors[index(f, coords)] = len(walkableNeighbors(f, coords))
+			if is_dead_end(coords) {
+				dead_ends = append(dead_ends, coords)
+			}
+		}
+	}
+
+	for len(dead_ends) > 0 {
+		coords := dead_ends[len(dead_ends)-1]
+		dead_ends = dead_ends[:len(dead_ends)-1]
+		filled[index(f, coords)] = true
+		if err := t.emit(DeadEnd, coords); err != nil {
+			return Result{Visited: t.visited}, err
+		}
+
+		for _, neighbor := range walkableNeighbors(f, coords) {
+			if filled[index(f, neighbor)] {
+				continue
+			}
+			open_neighbors[index(f, neighbor)]--
+			// only the step that turns the neighbor into a dead end queues it, so no cell is queued twice
+			if is_dead_end(neighbor) && open_neighbors[index(f, neighbor)] == 1 {
+				dead_ends = append(dead_ends, neighbor)
+			}
+		}
+	}
+
+	route, err := breadthFirst(f, t, func(from, to core.Coordinates) bool {
+		return !filled[index(f, to)]
+	})
+
+	return Result{Route: route, Visited: t.visited}, err
+}
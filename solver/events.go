@@ -0,0 +1,90 @@
+package solver
+
+import (
+	"context"
+	"sync"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Kind of step made by a solver
+type EventKind uint
+
+// Enum for possible steps made by a solver
+const (
+	Visit     EventKind = iota // solver stepped on or expanded the cell
+	Enqueue                    // cell was scheduled to be visited later
+	Backtrack                  // solver went back from the cell
+	DeadEnd                    // cell was marked as the one that does not lead to the finish
+	Found                      // finish was reached
+)
+
+// String representation of the event kind
+func (k EventKind) String() string {
+	switch k {
+	case Visit:
+		return "visit"
+	case Enqueue:
+		return "enqueue"
+	case Backtrack:
+		return "backtrack"
+	case DeadEnd:
+		return "dead end"
+	case Found:
+		return "found"
+	default:
+		return "unknown"
+	}
+}
+
+// Single step made by a solver
+type Event struct {
+	Kind   EventKind
+	Coords core.Coordinates
+}
+
+// Callback which receives every step made by a solver
+type Observer func(Event)
+
+// Create an observer which sends every event into the channel
+// Sending stops blocking the solver as soon as the context is done
+func ChannelObserver(ctx context.Context, events chan<- Event) Observer {
+	return func(e Event) {
+		select {
+		case events <- e:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// Collector of the steps made by a solver, safe to use from several goroutines
+type trace struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	observe Observer
+	visited []core.Coordinates
+}
+
+// Create a trace that reports steps to the observer, which can be nil
+func newTrace(ctx context.Context, observe Observer) *trace {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &trace{ctx: ctx, observe: observe}
+}
+
+// Record the step and report it to the observer
+// Returns an error if the context was cancelled or its deadline was exceeded
+func (t *trace) emit(kind EventKind, c core.Coordinates) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if kind == Visit || kind == DeadEnd {
+		t.visited = append(t.visited, c)
+	}
+	if t.observe != nil {
+		t.observe(Event{Kind: kind, Coords: c})
+	}
+
+	return t.ctx.Err()
+}
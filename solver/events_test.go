@@ -0,0 +1,121 @@
+package solver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Solve the labyrinth collecting every event the solver reports
+func collectEvents(t *testing.T, s Solver, f *core.Field) (Result, []Event) {
+	t.Helper()
+	events := make([]Event, 0)
+	result, err := s.Solve(context.Background(), f, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result, events
+}
+
+// Every kind of event is reported by the solvers making such steps, and the visited cells of the result are the reported ones
+func TestEventKinds(t *testing.T) {
+	f := newGeneratedField(t, "recursive-backtracker", 1)
+	solvers := map[EventKind]Solver{
+		Visit:     BreadthFirst{},
+		Enqueue:   AStar{Heuristic: ManhattanHeuristic{}},
+		Backtrack: Backtracker{},
+		DeadEnd:   DeadEndFilling{},
+		Found:     BreadthFirst{},
+	}
+	names := make(map[string]bool)
+	for kind, s := range solvers {
+		if name := kind.String(); name == "unknown" || names[name] {
+			t.Errorf("event kind %v has the name %q", uint(kind), name)
+		} else {
+			names[name] = true
+		}
+
+		result, events := collectEvents(t, s, f)
+		reported, visited := 0, make([]core.Coordinates, 0)
+		for _, e := range events {
+			if !e.Coords.IsValid(f.Width-1, f.Length-1) {
+				t.Errorf("%v: %v reported out of the field at %v", kind, e.Kind, e.Coords)
+			}
+			if e.Kind == kind {
+				reported++
+			}
+			if e.Kind == Visit || e.Kind == DeadEnd {
+				visited = append(visited, e.Coords)
+			}
+		}
+		if reported == 0 {
+			t.Errorf("%T reported no %v events", s, kind)
+		}
+		if len(visited) != len(result.Visited) {
+			t.Errorf("%T reported %v visited cells, the result has %v", s, len(visited), len(result.Visited))
+		} else {
+			for i := range visited {
+				if visited[i] != result.Visited[i] {
+					t.Errorf("%T reported %v as visited cell #%v, the result has %v", s, visited[i], i, result.Visited[i])
+					break
+				}
+			}
+		}
+		if kind == Found && (reported != 1 || events[len(events)-1] != Event{Kind: Found, Coords: f.Finish}) {
+			t.Errorf("%T reported %v found events, the last event is %+v", s, reported, events[len(events)-1])
+		}
+	}
+}
+
+// Channel observer delivers every event in the order the solver reported it
+func TestChannelObserverDelivers(t *testing.T) {
+	f := newGeneratedField(t, "kruskal", 1)
+	_, expected := collectEvents(t, BreadthFirst{}, f)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	channel := make(chan Event)
+	delivered := make(chan []Event)
+	go func() {
+		events := make([]Event, 0)
+		for e := range channel {
+			events = append(events, e)
+		}
+		delivered <- events
+	}()
+	_, err := BreadthFirst{}.Solve(ctx, f, ChannelObserver(ctx, channel))
+	close(channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := <-delivered
+	if len(events) != len(expected) {
+		t.Fatalf("channel delivered %v events, the solver reported %v", len(events), len(expected))
+	}
+	for i := range events {
+		if events[i] != expected[i] {
+			t.Fatalf("event #%v is %+v, expected %+v", i, events[i], expected[i])
+		}
+	}
+}
+
+// Channel observer stops waiting for the reader once the context is done
+func TestChannelObserverStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	observe := ChannelObserver(ctx, make(chan Event))
+	done := make(chan struct{})
+	go func() {
+		observe(Event{Kind: Visit})
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("observer kept waiting after the context was cancelled")
+	}
+}
@@ -0,0 +1,191 @@
+package solver
+
+import (
+	"context"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Agent that sees only the cells around itself and builds its own map of the labyrinth while walking
+// It heads to the finish once it has seen a way there, otherwise it goes to the closest unexplored place
+type Explorer struct {
+	Radius uint // how many neighborhood steps away the agent can see, walls do not block the view, 1 is used if it is not set
+	Moore  bool // see diagonal cells as well, using Moore's neighborhood instead of Von Neumann's
+}
+
+// Outcome of exploring the labyrinth
+type ExplorationReport struct {
+	Result            // route walked by the agent, it might go through the same cell several times
+	Steps        uint // amount of steps walked by the agent
+	OptimalSteps uint // amount of steps in the shortest route from start to finish
+}
+
+// Shifts of all cells within the view radius, the view spreads like a wave through the neighborhood
+func (e Explorer) viewShifts() [][2]int {
+	shifts := builder.NeumannShifts[:]
+	if e.Moore {
+		shifts = builder.MooreShifts[:]
+	}
+
+	radius := e.Radius
+	if radius == 0 {
+		radius = 1
+	}
+
+	seen := map[[2]int]bool{{0, 0}: true}
+	view, wave := [][2]int{{0, 0}}, [][2]int{{0, 0}}
+	for step := uint(0); step < radius; step++ {
+		next_wave := make([][2]int, 0)
+		for _, base := range wave {
+			for _, shift := range shifts {
+				cell := [2]int{base[0] + shift[0], base[1] + shift[1]}
+				if !seen[cell] {
+					seen[cell] = true
+					view, next_wave = append(view, cell), append(next_wave, cell)
+				}
+			}
+		}
+		wave = next_wave
+	}
+
+	return view
+}
+
+// Map of the labyrinth built by the agent
+type explorerMap struct {
+	f        *core.Field
+	known    []bool
+	walkable []bool
+}
+
+// Mark every cell within the view around the coordinates as known
+// Returns whether the finish was seen for the first time
+func (m *explorerMap) look(coords core.Coordinates, view [][2]int) bool {
+	finish_seen := false
+	for _, shift := range view {
+		seen := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
+		cell, err := m.f.At(seen)
+		if err != nil || m.known[index(m.f, seen)] {
+			continue
+		}
+		m.known[index(m.f, seen)], m.walkable[index(m.f, seen)] = true, cell.IsWalkable()
+		finish_seen = finish_seen || seen == m.f.Finish
+	}
+
+	return finish_seen
+}
+
+// Check that the known walkable cell is next to a cell that was not seen yet
+func (m *explorerMap) isFrontier(coords core.Coordinates) bool {
+	for _, shift := range builder.NeumannShifts {
+		neighbor := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
+		if neighbor.IsValid(m.f.Width-1, m.f.Length-1) && !m.known[index(m.f, neighbor)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Find the shortest path through known walkable cells to the closest cell accepted by is_target
+// The path does not include the starting cell, nil is returned if there is no target to reach
+func (m *explorerMap) planPath(from core.Coordinates, is_target func(core.Coordinates) bool) []core.Coordinates {
+	parents := newCellsArray(m.f, -1)
+	parents[index(m.f, from)] = index(m.f, from)
+	queue := []core.Coordinates{from}
+	for len(queue) > 0 {
+		coords := queue[0]
+		queue = queue[1:]
+		if coords != from && is_target(coords) {
+			route := routeFromParents(m.f, parents, from, coords)
+			path := make([]core.Coordinates, 0, route.Length-1)
+			it := route.GetIterator()
+			it()
+			for step, is_end := it(); !is_end; step, is_end = it() {
+				path = append(path, step)
+			}
+			return path
+		}
+
+		for _, neighbor := range walkableNeighbors(m.f, coords) {
+			if m.walkable[index(m.f, neighbor)] && parents[index(m.f, neighbor)] == -1 {
+				parents[index(m.f, neighbor)] = index(m.f, coords)
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Walk through the labyrinth seeing only the cells around the agent until the finish is reached
+func (e Explorer) Explore(ctx context.Context, f *core.Field, observe Observer) (ExplorationReport, error) {
+	if err := validateField(f); err != nil {
+		return ExplorationReport{}, err
+	}
+
+	t := newTrace(ctx, observe)
+	view := e.viewShifts()
+	m := explorerMap{f: f, known: make([]bool, f.Size()), walkable: make([]bool, f.Size())}
+	walked_cells := make([]bool, f.Size())
+	walked := []core.Coordinates{f.Start}
+	walked_cells[index(f, f.Start)] = true
+	m.look(f.Start, view)
+	if err := t.emit(Visit, f.Start); err != nil {
+		return ExplorationReport{Result: Result{Visited: t.visited}}, err
+	}
+	coords, plan, heading_to_finish := f.Start, []core.Coordinates{}, false
+
+	for coords != f.Finish {
+		if len(plan) == 0 || !heading_to_finish && !m.isFrontier(plan[len(plan)-1]) {
+			plan, heading_to_finish = nil, false
+			if m.known[index(f, f.Finish)] {
+				plan = m.planPath(coords, func(c core.Coordinates) bool { return c == f.Finish })
+				heading_to_finish = plan != nil
+			}
+			if plan == nil {
+				plan = m.planPath(coords, m.isFrontier)
+			}
+			if plan == nil {
+				return ExplorationReport{Result: Result{Visited: t.visited}}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+			}
+		}
+
+		coords, plan = plan[0], plan[1:]
+		walked = append(walked, coords)
+		kind := Visit
+		if walked_cells[index(f, coords)] {
+			kind = Backtrack
+		}
+		walked_cells[index(f, coords)] = true
+		if err := t.emit(kind, coords); err != nil {
+			return ExplorationReport{Result: Result{Visited: t.visited}}, err
+		}
+		if m.look(coords, view) && !heading_to_finish {
+			// the finish has just been seen, the current plan might not be the best one anymore
+			plan = nil
+		}
+	}
+	if err := t.emit(Found, coords); err != nil {
+		return ExplorationReport{Result: Result{Visited: t.visited}}, err
+	}
+
+	optimal, err := breadthFirst(f, nil, nil)
+	if err != nil {
+		return ExplorationReport{Result: Result{Visited: t.visited}}, err
+	}
+
+	return ExplorationReport{
+		Result:       Result{Route: routeFromSteps(walked), Visited: t.visited},
+		Steps:        uint(len(walked) - 1),
+		OptimalSteps: optimal.Length - 1,
+	}, nil
+}
+
+// Walk through the labyrinth seeing only the cells around the agent until the finish is reached
+func (e Explorer) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	report, err := e.Explore(ctx, f, observe)
+
+	return report.Result, err
+}
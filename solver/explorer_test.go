@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Views of the explorer that are tested, from the default one to the one seeing far around
+var explorers = []Explorer{{}, {Radius: 3}, {Radius: 2, Moore: true}}
+
+// Explorer reaches the finish walking at least as many steps as the shortest route has, and its route is the one it walked
+func TestExplorerWalksAtLeastOptimalSteps(t *testing.T) {
+	for _, algorithm := range []string{"recursive-backtracker", "route-growing", "dungeon"} {
+		for seed := int64(0); seed < 3; seed++ {
+			f := newGeneratedField(t, algorithm, seed)
+			shortest, err := Solve(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range explorers {
+				name := fmt.Sprintf("explorer %+v on %v, seed %v", e, algorithm, seed)
+				report, err := e.Explore(context.Background(), f, nil)
+				if err != nil {
+					t.Errorf("%v: %v", name, err)
+					continue
+				}
+				checkRoute(t, name, f, report.Route)
+				if report.OptimalSteps != shortest.Length-1 {
+					t.Errorf("%v: optimal steps are %v, the shortest route has %v", name, report.OptimalSteps, shortest.Length-1)
+				}
+				if report.Steps < report.OptimalSteps || report.Steps != report.Route.Length-1 {
+					t.Errorf("%v: walked %v steps with a route of %v cells, optimal steps are %v", name, report.Steps, report.Route.Length, report.OptimalSteps)
+				}
+			}
+		}
+	}
+}
+
+// Explorer that sees the whole field walks straight to the finish
+func TestExplorerSeeingEverythingWalksOptimally(t *testing.T) {
+	f := newGeneratedField(t, "kruskal", 1)
+	report, err := Explorer{Radius: f.Width + f.Length}.Explore(context.Background(), f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Steps != report.OptimalSteps {
+		t.Errorf("walked %v steps, optimal steps are %v", report.Steps, report.OptimalSteps)
+	}
+}
+
+// Explorer that runs out of places to explore reports that the labyrinth cannot be solved
+func TestExplorerUnsolvable(t *testing.T) {
+	f := newTestField(t, 5, 5, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 4, Y: 4}, column(2, 5)...)
+
+	for _, e := range explorers {
+		var unsolvable UnsolvableError
+		if report, err := e.Explore(context.Background(), f, nil); !errors.As(err, &unsolvable) {
+			t.Errorf("explorer %+v: expected the unsolvable error, got %v with route %v", e, err, report.Route)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package solver
+
+import (
+	"context"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Breadth-first search over every combination of a position and keys held
+// Picks up keys by stepping on them and opens doors of the colours it has keys for
+type KeysAndDoors struct{}
+
+// State of the search, a cell and a set of collected keys
+type keysState struct {
+	coords core.Coordinates
+	keys   uint // bit mask of collected key colours
+}
+
+// Find the shortest route from start to finish which collects keys needed to open doors on the way
+// The route might go through the same cell several times, e.g. when coming back from a key
+func (KeysAndDoors) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	const key_sets = 1 << core.KeyColours
+	stateIndex := func(s keysState) int { return index(f, s.coords)*key_sets + int(s.keys) }
+	t := newTrace(ctx, observe)
+	parents := make([]int, int(f.Size())*key_sets)
+	for i := range parents {
+		parents[i] = -1
+	}
+
+	start := keysState{coords: f.Start}
+	parents[stateIndex(start)] = stateIndex(start)
+	queue := []keysState{start}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if err := t.emit(Visit, state.coords); err != nil {
+			return Result{Visited: t.visited}, err
+		}
+
+		if state.coords == f.Finish {
+			steps := []core.Coordinates{}
+			for curr := stateIndex(state); ; curr = parents[curr] {
+				steps = append(steps, coordinatesAt(f, curr/key_sets))
+				if parents[curr] == curr {
+					break
+				}
+			}
+			for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+				steps[i], steps[j] = steps[j], steps[i]
+			}
+			err := t.emit(Found, state.coords)
+			return Result{Route: routeFromSteps(steps), Visited: t.visited}, err
+		}
+
+		for _, shift := range builder.NeumannShifts {
+			next := keysState{coords: core.Coordinates{X: state.coords.X + shift[0], Y: state.coords.Y + shift[1]}, keys: state.keys}
+			cell, err := f.At(next.coords)
+			if err != nil || !cell.IsWalkable() && !(cell.IsDoor() && state.keys&(1<<cell.Colour()) != 0) {
+				continue
+			}
+			if cell.IsKey() {
+				next.keys |= 1 << cell.Colour()
+			}
+			if parents[stateIndex(next)] == -1 {
+				parents[stateIndex(next)] = stateIndex(state)
+				queue = append(queue, next)
+				if err := t.emit(Enqueue, next.coords); err != nil {
+					return Result{Visited: t.visited}, err
+				}
+			}
+		}
+	}
+
+	return Result{Visited: t.visited}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+}
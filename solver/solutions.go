@@ -0,0 +1,155 @@
+package solver
+
+import (
+	"context"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// How many steps are made between the checks of the context while enumerating solutions
+const contextCheckInterval = 1024
+
+// Find all cells from which the finish can be reached
+func cellsLeadingToFinish(f *core.Field) []bool {
+	leads_to_finish := make([]bool, f.Size())
+	leads_to_finish[index(f, f.Finish)] = true
+	queue := []core.Coordinates{f.Finish}
+	for len(queue) > 0 {
+		coords := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range walkableNeighbors(f, coords) {
+			if !leads_to_finish[index(f, neighbor)] {
+				leads_to_finish[index(f, neighbor)] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return leads_to_finish
+}
+
+// Step on the route being enumerated
+type solutionStep struct {
+	coords     core.Coordinates
+	candidates []core.Coordinates // cells left to try after this one
+}
+
+// Go through simple routes from start to finish depth-first and pass each of them to on_route
+// Stops after 'limit' routes, returns whether the limit stopped the enumeration
+func enumerateSolutions(ctx context.Context, f *core.Field, limit uint, on_route func(steps []core.Coordinates)) (bool, error) {
+	if err := validateField(f); err != nil {
+		return false, err
+	}
+	if limit == 0 {
+		return false, f.Error("Limit of solutions to enumerate should be positive")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	leads_to_finish := cellsLeadingToFinish(f)
+	if !leads_to_finish[index(f, f.Start)] {
+		return false, nil
+	}
+
+	on_route_cells := make([]bool, f.Size())
+	// cells reachable from finish around the current route are marked with the number of the search
+	reachable, search := make([]uint, f.Size()), uint(0)
+	findCandidates := func(coords core.Coordinates) []core.Coordinates {
+		candidates := make([]core.Coordinates, 0, len(builder.NeumannShifts))
+		for _, neighbor := range walkableNeighbors(f, coords) {
+			if leads_to_finish[index(f, neighbor)] && !on_route_cells[index(f, neighbor)] {
+				candidates = append(candidates, neighbor)
+			}
+		}
+		if len(candidates) < 2 || coords == f.Finish {
+			return candidates
+		}
+
+		// the route can cut off some of the branches from the finish, they would never lead to a solution
+		search++
+		reachable[index(f, f.Finish)] = search
+		queue := []core.Coordinates{f.Finish}
+		for len(queue) > 0 {
+			curr := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range walkableNeighbors(f, curr) {
+				if reachable[index(f, neighbor)] != search && !on_route_cells[index(f, neighbor)] {
+					reachable[index(f, neighbor)] = search
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+		filtered := candidates[:0]
+		for _, candidate := range candidates {
+			if reachable[index(f, candidate)] == search {
+				filtered = append(filtered, candidate)
+			}
+		}
+
+		return filtered
+	}
+
+	on_route_cells[index(f, f.Start)] = true
+	stack := []solutionStep{{coords: f.Start, candidates: findCandidates(f.Start)}}
+	found, steps_made := uint(0), 0
+
+	for len(stack) > 0 {
+		if steps_made++; steps_made%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+		}
+
+		top := &stack[len(stack)-1]
+		if top.coords == f.Finish {
+			steps := make([]core.Coordinates, len(stack))
+			for i, step := range stack {
+				steps[i] = step.coords
+			}
+			on_route(steps)
+			if found++; found == limit {
+				return true, nil
+			}
+		}
+		if top.coords == f.Finish || len(top.candidates) == 0 {
+			on_route_cells[index(f, top.coords)] = false
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := top.candidates[len(top.candidates)-1]
+		top.candidates = top.candidates[:len(top.candidates)-1]
+		on_route_cells[index(f, next)] = true
+		stack = append(stack, solutionStep{coords: next, candidates: findCandidates(next)})
+	}
+
+	return false, nil
+}
+
+// Count simple routes from start to finish, counting stops as soon as 'limit' routes are found
+// Returns the amount of routes and whether the limit was reached, in which case there might be more of them
+func CountSolutions(ctx context.Context, f *core.Field, limit uint) (uint, bool, error) {
+	count := uint(0)
+	limit_reached, err := enumerateSolutions(ctx, f, limit, func([]core.Coordinates) { count++ })
+
+	return count, limit_reached, err
+}
+
+// List up to 'n' simple routes from start to finish
+func ListSolutions(ctx context.Context, f *core.Field, n uint) ([]core.Route, error) {
+	routes := make([]core.Route, 0)
+	_, err := enumerateSolutions(ctx, f, n, func(steps []core.Coordinates) {
+		routes = append(routes, routeFromSteps(steps))
+	})
+
+	return routes, err
+}
+
+// Check that there is exactly one simple route from start to finish, as in a perfect labyrinth
+func HasUniqueSolution(ctx context.Context, f *core.Field) (bool, error) {
+	count, _, err := CountSolutions(ctx, f, 2)
+
+	return count == 1, err
+}
@@ -0,0 +1,83 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Every simple route is counted once, counting stops at the limit
+func TestCountSolutions(t *testing.T) {
+	// empty 3x3 field has 12 simple routes between the opposite corners
+	f := newTestField(t, 3, 3, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 2, Y: 2})
+	if count, limit_reached, err := CountSolutions(context.Background(), f, 100); err != nil || count != 12 || limit_reached {
+		t.Errorf("counted %v routes with limit reached %v and error %v, expected 12 routes", count, limit_reached, err)
+	}
+	if count, limit_reached, err := CountSolutions(context.Background(), f, 5); err != nil || count != 5 || !limit_reached {
+		t.Errorf("counted %v routes with limit reached %v and error %v, expected 5 routes with limit reached", count, limit_reached, err)
+	}
+	if _, _, err := CountSolutions(context.Background(), f, 0); err == nil {
+		t.Error("routes were counted with limit 0")
+	}
+
+	f.Set(core.Wall, core.Coordinates{X: 1, Y: 0})
+	f.Set(core.Wall, core.Coordinates{X: 0, Y: 1})
+	if count, _, err := CountSolutions(context.Background(), f, 100); err != nil || count != 0 {
+		t.Errorf("counted %v routes with error %v on a field where finish cannot be reached", count, err)
+	}
+}
+
+// Listed routes are distinct simple routes from start to finish
+func TestListSolutions(t *testing.T) {
+	f := newTestField(t, 3, 3, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 2, Y: 2})
+	routes, err := ListSolutions(context.Background(), f, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 4 {
+		t.Fatalf("listed %v routes, expected 4", len(routes))
+	}
+
+	listed := make(map[string]bool)
+	for _, route := range routes {
+		checkRoute(t, "listed route", f, route)
+		steps := routeSteps(route)
+		seen := make(map[core.Coordinates]bool)
+		for _, step := range steps {
+			if seen[step] {
+				t.Errorf("route %v goes through %v more than once", route, step)
+			}
+			seen[step] = true
+		}
+		if key := fmt.Sprint(steps); listed[key] {
+			t.Errorf("route %v is listed more than once", route)
+		} else {
+			listed[key] = true
+		}
+	}
+}
+
+// Perfect labyrinths have a unique solution, empty fields do not
+func TestHasUniqueSolution(t *testing.T) {
+	for seed := int64(0); seed < 3; seed++ {
+		f := newGeneratedField(t, "recursive-backtracker", seed)
+		if unique, err := HasUniqueSolution(context.Background(), f); err != nil || !unique {
+			t.Errorf("seed %v: perfect labyrinth has unique solution %v, error %v\n%v", seed, unique, err, f)
+		}
+	}
+	if unique, err := HasUniqueSolution(context.Background(), newTestField(t, 3, 3, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 2, Y: 2})); err != nil || unique {
+		t.Errorf("empty field has unique solution %v, error %v", unique, err)
+	}
+}
+
+// Enumeration stops with the context error once the context is done
+func TestCountSolutionsStopsWithContext(t *testing.T) {
+	f := newTestField(t, 8, 8, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 7, Y: 7})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := CountSolutions(ctx, f, 1000000); err != context.Canceled {
+		t.Errorf("expected the context error, got %v", err)
+	}
+}
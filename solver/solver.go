@@ -1 +1,175 @@
+// Algorithms to find routes through a labyrinth
 package solver
+
+import (
+	"context"
+	"fmt"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Error returned when the finish cannot be reached from the start
+type UnsolvableError struct {
+	Start, Finish core.Coordinates
+}
+
+// String representation of the unsolvable error
+func (e UnsolvableError) Error() string {
+	return fmt.Sprintf("solver error: finish %v cannot be reached from start %v", e.Finish, e.Start)
+}
+
+// Outcome of solving a labyrinth
+type Result struct {
+	Route   core.Route
+	Visited []core.Coordinates // cells of Visit and DeadEnd events in the order they happened, might contain repetitions
+}
+
+// Algorithm that finds a route from start to finish
+// Every step is reported to the observer if it is not nil, solving stops as soon as the context is done
+type Solver interface {
+	Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error)
+}
+
+// Check that start and finish are placed within the field
+func validateField(f *core.Field) error {
+	if !f.Start.IsValid(f.Width-1, f.Length-1) || !f.Finish.IsValid(f.Width-1, f.Length-1) {
+		return f.Error("Start and/or finish are out of bounds or not set yet")
+	}
+
+	return nil
+}
+
+// Position of the coordinates in a flat array of all field cells
+func index(f *core.Field, c core.Coordinates) int {
+	return c.Y*int(f.Width) + c.X
+}
+
+// Coordinates of the cell at the chosen position of a flat array of all field cells
+func coordinatesAt(f *core.Field, idx int) core.Coordinates {
+	return core.Coordinates{X: idx % int(f.Width), Y: idx / int(f.Width)}
+}
+
+// Create a flat array of all field cells filled with the chosen value
+func newCellsArray(f *core.Field, value int) []int {
+	cells := make([]int, f.Size())
+	for i := range cells {
+		cells[i] = value
+	}
+
+	return cells
+}
+
+// Find all walkable cells in Von Neumann's neighborhood of the chosen coordinates
+func walkableNeighbors(f *core.Field, coords core.Coordinates) []core.Coordinates {
+	neighbors := make([]core.Coordinates, 0, len(builder.NeumannShifts))
+	for _, shift := range builder.NeumannShifts {
+		neighbor := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
+		if cell, err := f.At(neighbor); err == nil && cell.IsWalkable() {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+
+	return neighbors
+}
+
+// Create a route from a list of steps
+func routeFromSteps(steps []core.Coordinates) core.Route {
+	route := core.Route{}
+	if len(steps) == 0 {
+		return route
+	}
+	route.Init(steps[0])
+	for _, step := range steps[1:] {
+		route.Add(step)
+	}
+
+	return route
+}
+
+// Create a route by following parents back from the end to the start
+func routeFromParents(f *core.Field, parents []int, start, end core.Coordinates) core.Route {
+	steps := []core.Coordinates{end}
+	for curr := index(f, end); curr != index(f, start); curr = parents[curr] {
+		steps = append(steps, coordinatesAt(f, parents[curr]))
+	}
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+
+	return routeFromSteps(steps)
+}
+
+// Walkable neighbor of the cell in the chosen direction from builder.NeumannShifts
+func neighborAt(f *core.Field, coords core.Coordinates, direction int) (core.Coordinates, bool) {
+	shift := builder.NeumannShifts[direction]
+	neighbor := core.Coordinates{X: coords.X + shift[0], Y: coords.Y + shift[1]}
+	cell, err := f.At(neighbor)
+
+	return neighbor, err == nil && cell.IsWalkable()
+}
+
+// Find the shortest route from start to finish going only through the steps allowed by can_step
+// Every step is reported to the trace if it is provided
+func breadthFirst(f *core.Field, t *trace, can_step func(from, to core.Coordinates) bool) (core.Route, error) {
+	return breadthFirstBetween(f, t, f.Start, f.Finish, can_step)
+}
+
+// Find the shortest route between the chosen cells going only through the steps allowed by can_step
+// Every step is reported to the trace if it is provided
+func breadthFirstBetween(f *core.Field, t *trace, from, to core.Coordinates, can_step func(from, to core.Coordinates) bool) (core.Route, error) {
+	emit := func(kind EventKind, c core.Coordinates) error {
+		if t == nil {
+			return nil
+		}
+		return t.emit(kind, c)
+	}
+
+	parents := newCellsArray(f, -1)
+	parents[index(f, from)] = index(f, from)
+	queue := []core.Coordinates{from}
+	for len(queue) > 0 {
+		coords := queue[0]
+		queue = queue[1:]
+		if err := emit(Visit, coords); err != nil {
+			return core.Route{}, err
+		}
+		if coords == to {
+			return routeFromParents(f, parents, from, to), emit(Found, coords)
+		}
+
+		for _, neighbor := range walkableNeighbors(f, coords) {
+			if parents[index(f, neighbor)] == -1 && (can_step == nil || can_step(coords, neighbor)) {
+				parents[index(f, neighbor)] = index(f, coords)
+				queue = append(queue, neighbor)
+				if err := emit(Enqueue, neighbor); err != nil {
+					return core.Route{}, err
+				}
+			}
+		}
+	}
+
+	return core.Route{}, UnsolvableError{Start: from, Finish: to}
+}
+
+// Breadth-first search, always finds the shortest route
+type BreadthFirst struct{}
+
+// Find the shortest route from start to finish with breadth-first search
+func (BreadthFirst) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	t := newTrace(ctx, observe)
+	route, err := breadthFirst(f, t, nil)
+
+	return Result{Route: route, Visited: t.visited}, err
+}
+
+// Find the shortest route from start to finish with breadth-first search
+func Solve(f *core.Field) (core.Route, error) {
+	result, err := BreadthFirst{}.Solve(context.Background(), f, nil)
+
+	return result.Route, err
+}
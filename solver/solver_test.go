@@ -0,0 +1,186 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Create a field with the default configuration, start and finish in the chosen cells and walls in the listed ones
+func newTestField(t testing.TB, width, length uint, start, finish core.Coordinates, walls ...core.Coordinates) *core.Field {
+	t.Helper()
+	var f core.Field
+	if err := f.Init("../config.toml"); err != nil {
+		t.Fatal(err)
+	}
+	f.SetSize(width, length)
+	f.SetStartAndFinish(start, finish)
+	for _, wall := range walls {
+		f.Set(core.Wall, wall)
+	}
+
+	return &f
+}
+
+// List the cells of the column from the bottom row up to the chosen height
+func column(x, height int) []core.Coordinates {
+	cells := make([]core.Coordinates, height)
+	for y := range cells {
+		cells[y] = core.Coordinates{X: x, Y: y}
+	}
+
+	return cells
+}
+
+// Generate a 21x21 labyrinth on the test field with the chosen algorithm,
+// start in the bottom left corner and finish in the top right one
+func newGeneratedField(t testing.TB, algorithm string, seed int64) *core.Field {
+	t.Helper()
+	f := newTestField(t, 21, 21, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 20, Y: 20})
+	f.Configuration.Builder.Algorithm = algorithm
+	if _, err := builder.GenerateLabyrinth(f, builder.WithSeed(seed)); err != nil {
+		t.Fatalf("%v, seed %v: %v", algorithm, seed, err)
+	}
+
+	return f
+}
+
+// List every cell of the route in order
+func routeSteps(r core.Route) []core.Coordinates {
+	steps := make([]core.Coordinates, 0, r.Length)
+	it := r.GetIterator()
+	for coords, is_end := it(); !is_end; coords, is_end = it() {
+		steps = append(steps, coords)
+	}
+
+	return steps
+}
+
+// Check that the route goes from start to finish through walkable neighboring cells
+func checkRoute(t *testing.T, name string, f *core.Field, r core.Route) {
+	t.Helper()
+	steps := routeSteps(r)
+	if len(steps) == 0 || steps[0] != f.Start || steps[len(steps)-1] != f.Finish {
+		t.Errorf("%v: route %v does not go from start %v to finish %v", name, r, f.Start, f.Finish)
+		return
+	}
+	for i, step := range steps {
+		if cell, err := f.At(step); err != nil || !cell.IsWalkable() {
+			t.Errorf("%v: route %v goes through %v which is not walkable", name, r, step)
+		}
+		if i > 0 && abs(step.X-steps[i-1].X)+abs(step.Y-steps[i-1].Y) != 1 {
+			t.Errorf("%v: route %v jumps from %v to %v", name, r, steps[i-1], step)
+		}
+	}
+}
+
+// Absolute value of the integer
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// Breadth-first search finds the shortest route around the wall and reports when the wall is closed
+func TestBreadthFirst(t *testing.T) {
+	f := newTestField(t, 5, 5, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 4, Y: 0}, column(2, 4)...)
+
+	// up to the gap in the top row, through it and back down, 12 steps
+	route, err := Solve(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := BreadthFirst{}.Solve(context.Background(), f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, r := range map[string]core.Route{"solve": route, "breadth-first": result.Route} {
+		checkRoute(t, name, f, r)
+		if r.Length != 13 {
+			t.Errorf("%v: route has %v cells, the shortest one has 13", name, r.Length)
+		}
+	}
+
+	f.Set(core.Wall, core.Coordinates{X: 2, Y: 4})
+	var unsolvable UnsolvableError
+	if _, err := Solve(f); !errors.As(err, &unsolvable) {
+		t.Errorf("solve: expected the unsolvable error, got %v", err)
+	}
+	if _, err := (BreadthFirst{}).Solve(context.Background(), f, nil); !errors.As(err, &unsolvable) {
+		t.Errorf("breadth-first: expected the unsolvable error, got %v", err)
+	}
+}
+
+// Solvers walking the labyrinth the way a person would
+var classicSolvers = map[string]Solver{
+	"left wall follower":  WallFollower{},
+	"right wall follower": WallFollower{RightHand: true},
+	"tremaux":             Tremaux{},
+	"dead-end filling":    DeadEndFilling{},
+	"backtracker":         Backtracker{},
+}
+
+// Every classic solver reaches the finish, in perfect labyrinths they all find the only simple route
+func TestClassicSolvers(t *testing.T) {
+	for _, algorithm := range []string{"recursive-backtracker", "kruskal", "route-growing"} {
+		for seed := int64(0); seed < 3; seed++ {
+			f := newGeneratedField(t, algorithm, seed)
+			shortest, err := Solve(f)
+			if err != nil {
+				t.Fatalf("%v, seed %v: %v", algorithm, seed, err)
+			}
+			for name, s := range classicSolvers {
+				result, err := s.Solve(context.Background(), f, nil)
+				if err != nil {
+					t.Errorf("%v on %v, seed %v: %v", name, algorithm, seed, err)
+					continue
+				}
+				checkRoute(t, name, f, result.Route)
+				if len(result.Visited) == 0 {
+					t.Errorf("%v on %v, seed %v: no visited cells were traced", name, algorithm, seed)
+				}
+				if algorithm != "route-growing" && result.Route.Length != shortest.Length {
+					t.Errorf("%v on %v, seed %v: route has %v cells, the only simple one has %v", name, algorithm, seed, result.Route.Length, shortest.Length)
+				}
+			}
+		}
+	}
+}
+
+// Wall follower cannot reach the finish which does not touch any wall, other solvers can
+func TestWallFollowerCannotReachIsland(t *testing.T) {
+	f := newTestField(t, 5, 5, core.Coordinates{X: 0, Y: 0}, core.Coordinates{X: 2, Y: 2})
+
+	for name, s := range classicSolvers {
+		result, err := s.Solve(context.Background(), f, nil)
+		var unsolvable UnsolvableError
+		if _, is_wall_follower := s.(WallFollower); is_wall_follower {
+			if !errors.As(err, &unsolvable) {
+				t.Errorf("%v: expected the unsolvable error, got %v with route %v", name, err, result.Route)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+		checkRoute(t, name, f, result.Route)
+	}
+}
+
+// Solving stops with the context error once the context is done
+func TestClassicSolversStopWithContext(t *testing.T) {
+	f := newGeneratedField(t, "recursive-backtracker", 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for name, s := range classicSolvers {
+		if _, err := s.Solve(ctx, f, nil); err != context.Canceled {
+			t.Errorf("%v: expected the context error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package solver
+
+import (
+	"context"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Trémaux's algorithm, marks every passage when walking through it and never takes a passage marked twice
+type Tremaux struct{}
+
+// Find the first walkable direction with the passage marked the chosen amount of times, -1 if there is none
+func markedDirection(f *core.Field, coords core.Coordinates, mark func(core.Coordinates, int) *uint8, times uint8) int {
+	for direction := range builder.NeumannShifts {
+		if _, ok := neighborAt(f, coords, direction); ok && *mark(coords, direction) == times {
+			return direction
+		}
+	}
+
+	return -1
+}
+
+// Walk through the labyrinth marking passages until the finish is reached
+// The route consists of the passages that were marked only once
+func (Tremaux) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	directions_count := len(builder.NeumannShifts)
+	opposite := func(direction int) int { return (direction + directions_count/2) % directions_count }
+	marks := make([]uint8, int(f.Size())*directions_count)
+	mark := func(coords core.Coordinates, direction int) *uint8 {
+		return &marks[index(f, coords)*directions_count+direction]
+	}
+
+	t := newTrace(ctx, observe)
+	visited := make([]bool, f.Size())
+	coords, came_from, visited_before := f.Start, -1, false
+	visited[index(f, coords)] = true
+
+	for coords != f.Finish {
+		if err := t.emit(Visit, coords); err != nil {
+			return Result{Visited: t.visited}, err
+		}
+
+		next_direction := -1
+		if came_from != -1 && visited_before && *mark(coords, came_from) == 1 {
+			// new passage led to a known cell, turn back
+			next_direction = came_from
+			if err := t.emit(Backtrack, coords); err != nil {
+				return Result{Visited: t.visited}, err
+			}
+		} else if next_direction = markedDirection(f, coords, mark, 0); next_direction == -1 {
+			// no new passages left, prefer going back the same way
+			if came_from != -1 && *mark(coords, came_from) == 1 {
+				next_direction = came_from
+				if err := t.emit(DeadEnd, coords); err != nil {
+					return Result{Visited: t.visited}, err
+				}
+			} else {
+				next_direction = markedDirection(f, coords, mark, 1)
+			}
+		}
+		if next_direction == -1 {
+			return Result{Visited: t.visited}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+		}
+
+		next, _ := neighborAt(f, coords, next_direction)
+		*mark(coords, next_direction)++
+		*mark(next, opposite(next_direction))++
+		coords, came_from = next, opposite(next_direction)
+		visited_before = visited[index(f, coords)]
+		visited[index(f, coords)] = true
+	}
+	if err := t.emit(Visit, coords); err != nil {
+		return Result{Visited: t.visited}, err
+	}
+	if err := t.emit(Found, coords); err != nil {
+		return Result{Visited: t.visited}, err
+	}
+
+	route, err := breadthFirst(f, nil, func(from, to core.Coordinates) bool {
+		for direction := range builder.NeumannShifts {
+			if next, _ := neighborAt(f, from, direction); next == to {
+				return *mark(from, direction) == 1
+			}
+		}
+		return false
+	})
+
+	return Result{Route: route, Visited: t.visited}, err
+}
@@ -0,0 +1,78 @@
+package solver
+
+import (
+	"context"
+
+	builder "github.com/Via-R/labyrinth-go/builder"
+	core "github.com/Via-R/labyrinth-go/core"
+)
+
+// Keep one hand on the wall and walk until the finish is found
+// Cannot solve labyrinths where the finish is not connected to the walls along the start, e.g. when it is an island
+type WallFollower struct {
+	RightHand bool // follow the wall on the right side instead of the left one
+}
+
+// Walk along the wall from start until the finish is reached or the walker comes back into the same state
+// The route has all the loops made by the walker removed, while the visited cells keep every step
+func (w WallFollower) Solve(ctx context.Context, f *core.Field, observe Observer) (Result, error) {
+	if err := validateField(f); err != nil {
+		return Result{}, err
+	}
+
+	// directions in builder.NeumannShifts go clockwise, so turning right is the next direction and left is the previous one
+	directions_count := len(builder.NeumannShifts)
+	turns := []int{directions_count - 1, 0, 1, 2}
+	if w.RightHand {
+		turns = []int{1, 0, directions_count - 1, 2}
+	}
+
+	t := newTrace(ctx, observe)
+	seen_states := make([]bool, int(f.Size())*directions_count)
+	route_positions := newCellsArray(f, -1)
+	route_steps := []core.Coordinates{f.Start}
+	route_positions[index(f, f.Start)] = 0
+	coords, direction := f.Start, 0
+
+	for {
+		if err := t.emit(Visit, coords); err != nil {
+			return Result{Visited: t.visited}, err
+		}
+		if coords == f.Finish {
+			err := t.emit(Found, coords)
+			return Result{Route: routeFromSteps(route_steps), Visited: t.visited}, err
+		}
+
+		state := index(f, coords)*directions_count + direction
+		if seen_states[state] {
+			return Result{Visited: t.visited}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+		}
+		seen_states[state] = true
+
+		moved := false
+		for _, turn := range turns {
+			next_direction := (direction + turn) % directions_count
+			if next, ok := neighborAt(f, coords, next_direction); ok {
+				coords, direction, moved = next, next_direction, true
+				break
+			}
+		}
+		if !moved {
+			return Result{Visited: t.visited}, UnsolvableError{Start: f.Start, Finish: f.Finish}
+		}
+
+		// cut off the loop if the walker came back to the cell which is already on the route
+		if position := route_positions[index(f, coords)]; position != -1 {
+			for i := len(route_steps) - 1; i > position; i-- {
+				route_positions[index(f, route_steps[i])] = -1
+				if err := t.emit(Backtrack, route_steps[i]); err != nil {
+					return Result{Visited: t.visited}, err
+				}
+			}
+			route_steps = route_steps[:position+1]
+		} else {
+			route_positions[index(f, coords)] = len(route_steps)
+			route_steps = append(route_steps, coords)
+		}
+	}
+}